@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runCheck checks that every game file has exactly one solution.
+// Game files can be passed with -game or as arguments.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	var ro renderOptions
+	ro.register(fs)
+	fs.Parse(args[1:])
+
+	files := fs.Args()
+	if *gameFile != "" {
+		files = append([]string{*gameFile}, files...)
+	}
+	if len(files) == 0 {
+		return errors.New("no game file given")
+	}
+
+	var errs []error
+	for _, f := range files {
+		if err := checkFile(f, &ro); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkFile(gameFile string, ro *renderOptions) error {
+	g, err := loadGame(gameFile)
+	if err != nil {
+		return err
+	}
+	res, err := board1.CheckUnique(g)
+	if err != nil {
+		return err
+	}
+	if !res.Unique {
+		fmt.Printf("%s: not unique, two of the solutions:\n", gameFile)
+		if err := ro.print(g, res.Solutions[0]); err != nil {
+			return err
+		}
+		fmt.Println()
+		if err := ro.print(g, res.Solutions[1]); err != nil {
+			return err
+		}
+		return board1.ErrNotUnique
+	}
+	fmt.Printf("%s: unique\n", gameFile)
+	return nil
+}
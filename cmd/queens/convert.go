@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runConvert writes a puzzle in the text format or as JSON.
+// The input can be in any format loadPuzzle reads, a screenshot included.
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file or screenshot")
+	asJSON := fs.Bool("json", false, "write the puzzle as JSON")
+	out := fs.String("o", "", "write the puzzle to `file` instead of stdout")
+	fs.Parse(args[1:])
+
+	if *gameFile == "" && fs.NArg() > 0 {
+		*gameFile = fs.Arg(0)
+	}
+	if *gameFile == "" {
+		return errors.New("no game file given")
+	}
+	p, err := loadPuzzle(*gameFile)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *asJSON {
+		return board1.EncodePuzzle(w, p)
+	}
+	return board1.WriteAreas(w, p.Size.Rows, p.Size.Cols, p.Areas)
+}
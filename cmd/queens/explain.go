@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runExplain prints the steps that solve the game.
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	var ro renderOptions
+	ro.register(fs)
+	fs.Parse(args[1:])
+
+	g, err := loadGame(*gameFile)
+	if err != nil {
+		return err
+	}
+	steps, err := board1.Explain(g)
+	for i, s := range steps {
+		fmt.Printf("step %d: %s\n", i+1, s.Reason)
+		if s.Queen != nil {
+			fmt.Printf("queen at %s\n", board1.FieldName(s.Queen))
+		}
+		if len(s.Blocked) > 0 {
+			fmt.Printf("blocked: %v\n", fieldNames(s.Blocked...))
+		}
+		if err := ro.print(g, s.Board); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	if errors.Is(err, board1.ErrStuck) {
+		return fmt.Errorf("stuck after %d steps, the puzzle needs guessing: %w", len(steps), err)
+	}
+	return err
+}
+
+// fieldNames returns the names of the positions, as shown in the reasons.
+func fieldNames(ps ...board1.Position) []string {
+	names := make([]string, len(ps))
+	for i, p := range ps {
+		names[i] = board1.FieldName(p)
+	}
+	return names
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+
+	"github.com/myhops/queens/pkg/board1"
+	"github.com/myhops/queens/pkg/generate"
+)
+
+// runGenerate writes a new puzzle with exactly one solution.
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	size := fs.Int("size", 8, "size of the board")
+	seed := fs.Uint64("seed", 0, "random seed, a random seed is used if 0")
+	difficulty := fs.String("difficulty", "", "difficulty of the puzzle: easy, medium, hard, expert or guessing")
+	out := fs.String("o", "", "write the puzzle to `file` instead of stdout")
+	asJSON := fs.Bool("json", false, "write the puzzle with its solution as JSON")
+	fs.Parse(args[1:])
+
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	gen := generate.New(*size, *seed)
+	var p *generate.Puzzle
+	var err error
+	if *difficulty == "" {
+		p, err = gen.Generate()
+	} else {
+		tier, terr := board1.ParseTier(*difficulty)
+		if terr != nil {
+			return terr
+		}
+		p, err = gen.GenerateTier(tier)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "seed: %d\n", *seed)
+	if *difficulty != "" {
+		fmt.Fprintf(os.Stderr, "rating: %s\n", p.Rating)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *asJSON {
+		jp := &board1.Puzzle{
+			Size:     board1.Size{Rows: p.Size, Cols: p.Size},
+			Areas:    p.Areas,
+			Solution: p.Queens,
+			Meta:     &board1.Meta{Source: fmt.Sprintf("queens generate -seed %d", *seed)},
+		}
+		if *difficulty != "" {
+			jp.Meta.Difficulty = p.Rating.Tier.String()
+		}
+		return board1.EncodePuzzle(w, jp)
+	}
+	return p.Write(w)
+}
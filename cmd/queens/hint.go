@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runHint prints the next move from a position, without the solution.
+func runHint(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	positionFile := fs.String("position", "", "position file, the start position of the game if not set")
+	out := fs.String("o", "", "write the position after the hint to `file`")
+	var ro renderOptions
+	ro.register(fs)
+	fs.Parse(args[1:])
+
+	p, err := loadPuzzle(*gameFile)
+	if err != nil {
+		return err
+	}
+	g := p.Game()
+	b, err := loadPosition(g, p, *positionFile)
+	if err != nil {
+		return err
+	}
+
+	s, err := board1.Hint(g, b)
+	if errors.Is(err, board1.ErrSolved) {
+		fmt.Println("solved")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hint: %s\n", s.Reason)
+	if s.Queen != nil {
+		fmt.Printf("queen at %s\n", board1.FieldName(s.Queen))
+	}
+	if len(s.Blocked) > 0 {
+		fmt.Printf("blocked: %v\n", fieldNames(s.Blocked...))
+	}
+	if err := ro.print(g, s.Board); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return nil
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return board1.WritePosition(f, s.Board)
+}
+
+// loadPosition loads the position file, or returns the start position
+// of p if there is none.
+func loadPosition(g *board1.Game, p *board1.Puzzle, positionFile string) (*board1.Board, error) {
+	if positionFile == "" {
+		return p.Position(g)
+	}
+	r, err := os.Open(positionFile)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return board1.LoadPosition(r, g.Rows, g.Cols)
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"runtime/pprof"
 	"time"
 
 	"github.com/myhops/queens/pkg/board1"
+	"github.com/myhops/queens/pkg/screenshot"
 )
 
 type Options struct {
@@ -17,6 +24,45 @@ type Options struct {
 	cpuProfile string
 	solver     string
 	sheet      string
+	all        bool
+	json       bool
+	stats      string
+	timeout    time.Duration
+	budget     board1.Budget
+	stars      int
+	adjacency  string
+	diagonals  bool
+	render     renderOptions
+}
+
+// renderOptions selects how boards are printed.
+type renderOptions struct {
+	color       string
+	hideBlocked bool
+}
+
+func (o *renderOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.color, "color", "auto", "colors of the board: auto, plain, 256 or truecolor")
+	fs.BoolVar(&o.hideBlocked, "hideblocked", false, "do not show blocked fields")
+}
+
+// print prints b with the area colours of g.
+func (o *renderOptions) print(g *board1.Game, b *board1.Board) error {
+	m, err := o.mode()
+	if err != nil {
+		return err
+	}
+	r := board1.Renderer{Mode: m, HideBlocked: o.hideBlocked}
+	return r.Render(os.Stdout, g, b)
+}
+
+// mode returns the color mode. With color auto the colors are only used
+// when stdout is a terminal.
+func (o *renderOptions) mode() (board1.ColorMode, error) {
+	if o.color == "auto" || o.color == "" {
+		return board1.DetectColorMode(os.Stdout), nil
+	}
+	return board1.ParseColorMode(o.color)
 }
 
 func getOptions(args []string) *Options {
@@ -25,48 +71,146 @@ func getOptions(args []string) *Options {
 	fs.StringVar(&o.gameFile, "game", "", "game file")
 	fs.StringVar(&o.memProfile, "memprofile", "", "write memory profile to `file`")
 	fs.StringVar(&o.cpuProfile, "cpuprofile", "", "write cpu profile to `file`")
-	fs.StringVar(&o.solver, "solver", "area", "solver to use")
+	fs.StringVar(&o.solver, "solver", "area", "solver to use: simple, area, dlx, bit, propagate or parallel")
 	fs.StringVar(&o.sheet, "sheet", "queens", "Google sheet to use")
+	fs.BoolVar(&o.all, "all", false, "print all solutions")
+	fs.BoolVar(&o.json, "json", false, "print the puzzle with the solution as JSON")
+	fs.StringVar(&o.stats, "stats", "text", "print the solve statistics as text or json, or none")
+	fs.DurationVar(&o.timeout, "timeout", 0, "stop solving after `duration`, 0 for no limit")
+	fs.IntVar(&o.budget.Nodes, "nodes", 0, "stop solving after this many search nodes, 0 for no limit")
+	fs.Int64Var(&o.budget.Placements, "placements", 0, "stop solving after this many queens placed, 0 for no limit")
+	fs.IntVar(&o.stars, "stars", 0, "queens per row, column and area, the stars of the puzzle if 0")
+	fs.BoolVar(&o.diagonals, "diagonals", false, "queens attack along their full diagonals too, like chess queens")
+	fs.StringVar(&o.adjacency, "adjacency", "", "fields a queen blocks around it: king, orthogonal, none or knight, the rule of the puzzle if not set")
+	o.render.register(fs)
 	fs.Parse(args[1:])
 	return o
 }
 
+// printStats prints stats in format text or json, format none prints nothing.
+func printStats(w io.Writer, format string, stats board1.SolveStats) error {
+	switch format {
+	case "text", "":
+		_, err := fmt.Fprint(w, stats)
+		return err
+	case "json":
+		return json.NewEncoder(w).Encode(stats)
+	case "none":
+		return nil
+	default:
+		return fmt.Errorf("unknown stats format %q", format)
+	}
+}
+
 func getSolver(s string) board1.Solver {
 	switch s {
 	case "simple":
 		return &board1.SimpleSolver{}
 	case "area":
 		return &board1.AreaSolver{}
+	case "dlx":
+		return &board1.DLXSolver{}
+	case "bit":
+		return &board1.BitSolver{}
+	case "propagate":
+		return &board1.PropagationSolver{}
+	case "parallel":
+		return &board1.ParallelSolver{}
 	default:
 		return &board1.AreaSolver{}
 	}
 }
 
-func loadAreas(gameFile string) ([]board1.Area, int, error) {
-	// Load the board
+// loadPuzzle loads a puzzle in JSON, in the text format or from a PNG or
+// JPEG screenshot. The format is detected from the content.
+func loadPuzzle(gameFile string) (*board1.Puzzle, error) {
 	r, err := os.Open(gameFile)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer r.Close()
 
-	a, i, err := board1.LoadAreas(r)
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(4); isImage(head) {
+		a, i, err := screenshot.Load(br)
+		if err != nil {
+			return nil, err
+		}
+		return board1.NewPuzzle(board1.NewGame(i, i, a...)), nil
+	}
+	return board1.LoadPuzzle(br)
+}
+
+// isImage reports whether head is the start of a PNG or JPEG file.
+func isImage(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("\x89PNG")) || bytes.HasPrefix(head, []byte("\xff\xd8\xff"))
+}
+
+func loadGame(gameFile string) (*board1.Game, error) {
+	// The loader validates the board.
+	p, err := loadPuzzle(gameFile)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	return p.Game(), nil
+}
+
+func printSolutions(g *board1.Game, s board1.Solver, ro *renderOptions) error {
+	e, ok := s.(board1.Enumerator)
+	if !ok {
+		return fmt.Errorf("solver %T cannot enumerate solutions", s)
 	}
-	return a, i, nil
+	var n int
+	for b, err := range g.Solutions(e) {
+		if err != nil {
+			return err
+		}
+		n++
+		fmt.Printf("solution %d:\n", n)
+		if err := ro.print(g, b); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("number of solutions: %d\n", n)
+	return nil
+}
+
+// commands holds the subcommands, the game is solved when none is given.
+var commands = map[string]func(args []string) error{
+	"check":    runCheck,
+	"convert":  runConvert,
+	"explain":  runExplain,
+	"generate": runGenerate,
+	"hint":     runHint,
+	"nqueens":  runNQueens,
+	"play":     runPlay,
+	"rate":     runRate,
+	"render":   runRender,
+	"serve":    runServe,
+	"validate": runValidate,
 }
 
 func run(args []string) error {
+	if len(args) > 1 {
+		if cmd, ok := commands[args[1]]; ok {
+			return cmd(args[1:])
+		}
+	}
+
 	logger := slog.Default().With(
 		"method", "run",
 	)
+	o := getOptions(args)
+
+	// Keep stdout for the JSON.
+	var info io.Writer = os.Stdout
+	if o.json {
+		info = os.Stderr
+	}
 	defer func(start time.Time) {
-		fmt.Printf("queens took %v\n", time.Since(start))
+		fmt.Fprintf(info, "queens took %v\n", time.Since(start))
 	}(time.Now())
 
-	o := getOptions(args)
-
 	// Start profiling
 	if o.cpuProfile != "" {
 		f, err := os.Create(o.cpuProfile)
@@ -80,53 +224,81 @@ func run(args []string) error {
 		defer pprof.StopCPUProfile()
 	}
 
-	a, i, err := loadAreas(o.gameFile)
+	p, err := loadPuzzle(o.gameFile)
 	if err != nil {
 		return err
 	}
-	logger.Debug("loaded areas", "count", len(a), "board_size", i)
-	if len(a) != i {
-		return fmt.Errorf("areas and board size do not match, areas: %d, board size: %d", len(a), i)
+	if o.stars > 0 {
+		p.Stars = o.stars
 	}
-
-	g := board1.NewGame(i, i, a...)
+	if o.diagonals {
+		p.Diagonals = true
+	}
+	if o.adjacency != "" {
+		if p.Adjacency, err = board1.ParseAdjacency(o.adjacency); err != nil {
+			return err
+		}
+	}
+	g := p.Game()
+	logger.Debug("loaded areas", "count", len(g.Areas), "rows", g.Rows, "cols", g.Cols, "holes", len(g.Holes))
 	// solve
 
 	s := getSolver(o.solver)
+	if o.all {
+		return printSolutions(g, s, &o.render)
+	}
 	// run in func to ease timing
-	b, err := func() (*board1.Board, error) {
+	b, stats, err := func() (*board1.Board, board1.SolveStats, error) {
 		defer func(start time.Time) {
-			fmt.Printf("solve took %v\n", time.Since(start))
+			fmt.Fprintf(info, "solve took %v\n", time.Since(start))
 		}(time.Now())
 
-		b, err := g.Solve(s)
-		if err != nil {
-			return nil, err
+		ctx := context.Background()
+		if o.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.timeout)
+			defer cancel()
 		}
-		return b, nil
+		return g.SolveContext(ctx, s, o.budget)
 	}()
 
 	if err != nil {
+		// The counts of a stopped search show how far it got.
+		var se *board1.StoppedError
+		if errors.As(err, &se) {
+			printStats(info, o.stats, se.Stats)
+		}
+		return err
+	}
+	if err := writeMemProfile(o.memProfile); err != nil {
 		return err
 	}
 
-	b.Print()
-	fmt.Printf("number of times queen placed: %d\n", g.QueenPlaced())
-	fmt.Printf("solve called: %d\n", g.SolveCalled())
-	fmt.Printf("boards used: %d\n", g.BoardPool.MaxEntries())
-
-	if o.memProfile != "" {
-		f, err := os.Create(o.memProfile)
-		if err != nil {
+	if o.json {
+		p.SetSolution(b)
+		if err := board1.EncodePuzzle(os.Stdout, p); err != nil {
 			return err
 		}
-		defer f.Close()
+		return printStats(info, o.stats, stats)
+	}
+	if err := o.render.print(g, b); err != nil {
+		return err
+	}
+	return printStats(os.Stdout, o.stats, stats)
+}
 
-		if err := pprof.WriteHeapProfile(f); err != nil {
-			return err
-		}
+// writeMemProfile writes a heap profile to path, if path is set.
+func writeMemProfile(path string) error {
+	if path == "" {
+		return nil
 	}
-	return nil
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return pprof.WriteHeapProfile(f)
 }
 
 func main() {
@@ -135,5 +307,8 @@ func main() {
 		"application", "queens")
 	slog.SetDefault(logger)
 
-	run(os.Args)
+	if err := run(os.Args); err != nil {
+		logger.Error("queens failed", "error", err)
+		os.Exit(1)
+	}
 }
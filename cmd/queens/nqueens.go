@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runNQueens solves the chess N-queens puzzle, or counts its solutions.
+func runNQueens(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	size := fs.Int("size", 8, "size of the board")
+	solver := fs.String("solver", "bit", "solver to use: simple, area, dlx, bit, propagate or parallel")
+	count := fs.Bool("count", false, "count all solutions")
+	stats := fs.String("stats", "text", "print the solve statistics as text or json, or none")
+	timeout := fs.Duration("timeout", 0, "stop after `duration`, 0 for no limit")
+	var ro renderOptions
+	ro.register(fs)
+	fs.Parse(args[1:])
+
+	if *size < 1 {
+		return fmt.Errorf("size %d is not positive", *size)
+	}
+	g := board1.NQueens(*size)
+	s := getSolver(*solver)
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	if *count {
+		e, ok := s.(board1.Enumerator)
+		if !ok {
+			return fmt.Errorf("solver %T cannot enumerate solutions", s)
+		}
+		n, st, err := g.CountSolutionsContext(ctx, e, 0, board1.Budget{})
+		if err != nil {
+			var se *board1.StoppedError
+			if errors.As(err, &se) {
+				fmt.Printf("counted %d solutions before stopping\n", n)
+				printStats(os.Stdout, *stats, se.Stats)
+			}
+			return err
+		}
+		fmt.Printf("number of solutions: %d\n", n)
+		return printStats(os.Stdout, *stats, st)
+	}
+
+	b, st, err := g.SolveContext(ctx, s, board1.Budget{})
+	if err != nil {
+		var se *board1.StoppedError
+		if errors.As(err, &se) {
+			printStats(os.Stdout, *stats, se.Stats)
+		}
+		return err
+	}
+	if err := ro.print(g, b); err != nil {
+		return err
+	}
+	return printStats(os.Stdout, *stats, st)
+}
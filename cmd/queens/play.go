@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/myhops/queens/pkg/board1"
+	"github.com/myhops/queens/pkg/play"
+)
+
+// runPlay plays a game in the terminal.
+func runPlay(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	positionFile := fs.String("position", "", "position file, the start position of the game if not set")
+	var ro renderOptions
+	fs.StringVar(&ro.color, "color", "auto", "colors of the board: auto, plain, 256 or truecolor")
+	fs.Parse(args[1:])
+
+	p, err := loadPuzzle(*gameFile)
+	if err != nil {
+		return err
+	}
+	g := p.Game()
+	b, err := loadPosition(g, p, *positionFile)
+	if err != nil {
+		return err
+	}
+	m, err := play.New(g, b)
+	if err != nil {
+		return err
+	}
+	mode, err := ro.mode()
+	if err != nil {
+		return err
+	}
+
+	if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return errors.New("play needs a terminal")
+	}
+	restore, err := rawMode()
+	if err != nil {
+		return err
+	}
+	// Use the alternate screen without a cursor.
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	err = playGame(m, os.Stdin, os.Stdout, mode)
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	restore()
+	if err != nil {
+		return err
+	}
+	if m.Solved() {
+		fmt.Printf("solved in %v\n", m.Elapsed().Round(time.Second))
+	}
+	return nil
+}
+
+// playGame handles the keys read from in and draws m on out after every
+// key, and every second for the timer. It returns when the player quits
+// or in ends.
+func playGame(m *play.Model, in io.Reader, out io.Writer, mode board1.ColorMode) error {
+	input := make(chan []byte)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				select {
+				case input <- bytes.Clone(buf[:n]):
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	var rest []byte
+	for {
+		if err := m.Render(out, mode); err != nil {
+			return err
+		}
+		select {
+		case b := <-input:
+			var keys []play.Key
+			keys, rest = play.ParseKeys(append(rest, b...))
+			for _, k := range keys {
+				if !m.Handle(k) {
+					return nil
+				}
+			}
+		case err := <-readErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-tick.C:
+		}
+	}
+}
+
+// rawMode switches the terminal on stdin to raw mode with stty, and
+// returns a function to switch it back.
+func rawMode() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runRate prints the difficulty of every game file.
+// Game files can be passed with -game or as arguments.
+func runRate(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	sorted := fs.Bool("sort", false, "sort the games from easy to hard")
+	fs.Parse(args[1:])
+
+	files := fs.Args()
+	if *gameFile != "" {
+		files = append([]string{*gameFile}, files...)
+	}
+	if len(files) == 0 {
+		return errors.New("no game file given")
+	}
+
+	type rated struct {
+		file   string
+		rating board1.Rating
+	}
+	var res []rated
+	for _, f := range files {
+		g, err := loadGame(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f, err)
+		}
+		res = append(res, rated{file: f, rating: board1.Rate(g)})
+	}
+	if *sorted {
+		slices.SortStableFunc(res, func(a, b rated) int {
+			return a.rating.Score - b.rating.Score
+		})
+	}
+	for _, r := range res {
+		fmt.Printf("%s: %s\n", r.file, r.rating)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/myhops/queens/pkg/board1"
+	"github.com/myhops/queens/pkg/render"
+)
+
+// runRender draws the puzzle, or its solution, as an SVG or PNG image.
+func runRender(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	format := fs.String("format", "", "image format: svg or png, taken from the -o extension if not set")
+	out := fs.String("o", "", "write the image to `file` instead of stdout")
+	solve := fs.Bool("solve", false, "draw the solution")
+	positionFile := fs.String("position", "", "draw the position in `file`")
+	marks := fs.Bool("marks", false, "draw crosses on blocked fields")
+	field := fs.Int("field", render.DefaultField, "width of a field in pixels")
+	fs.Parse(args[1:])
+
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*out), ".")
+	}
+	draw := render.SVG
+	switch *format {
+	case "svg", "":
+	case "png":
+		draw = render.PNG
+	default:
+		return fmt.Errorf("unknown image format %q", *format)
+	}
+
+	p, err := loadPuzzle(*gameFile)
+	if err != nil {
+		return err
+	}
+	g := p.Game()
+	var b *board1.Board
+	switch {
+	case *solve && len(p.Solution) > 0:
+		b = g.NewBoard()
+		g.PlaceQueens(b, p.Solution)
+	case *solve:
+		if b, _, err = g.Solve(&board1.BitSolver{}); err != nil {
+			return err
+		}
+	case *positionFile != "" || len(p.Queens) > 0 || len(p.Blocked) > 0:
+		if b, err = loadPosition(g, p, *positionFile); err != nil {
+			return err
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return draw(w, g, b, render.Options{Field: *field, Marks: *marks})
+}
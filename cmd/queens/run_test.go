@@ -1,6 +1,19 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/myhops/queens/pkg/board1"
+	"github.com/myhops/queens/pkg/play"
+)
 
 func TestArea(t *testing.T) {
 	args := []string{"bt", "-game", "../../2025-4-22.txt", "-solver", "simple"}
@@ -10,3 +23,292 @@ func TestArea(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParallel(t *testing.T) {
+	args := []string{"bt", "-game", "board.txt", "-solver", "parallel", "-all"}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	args := []string{"bt", "-game", "../../2025-4-22.txt", "-solver", "simple", "-nodes", "3"}
+	if err := run(args); !errors.Is(err, board1.ErrBudget) {
+		t.Errorf("got error %v, want %v", err, board1.ErrBudget)
+	}
+}
+
+func TestStats(t *testing.T) {
+	for _, format := range []string{"text", "json", "none"} {
+		args := []string{"bt", "-game", "../../2025-04-23.txt", "-solver", "bit", "-stats", format}
+		if err := run(args); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+	args := []string{"bt", "-game", "../../2025-04-23.txt", "-stats", "xml"}
+	if err := run(args); err == nil {
+		t.Error("no error for stats format xml")
+	}
+}
+
+func TestMemProfile(t *testing.T) {
+	for _, extra := range [][]string{nil, {"-json"}} {
+		profile := filepath.Join(t.TempDir(), "mem.prof")
+		args := append([]string{"bt", "-game", "../../2025-04-23.txt", "-memprofile", profile}, extra...)
+		if err := run(args); err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Stat(profile); err != nil || fi.Size() == 0 {
+			t.Errorf("%v: no memory profile written: %v", args[1:], err)
+		}
+	}
+}
+
+func TestStars(t *testing.T) {
+	for _, args := range [][]string{
+		{"bt", "-game", "../../pkg/board1/testdata/star-battle.json", "-solver", "dlx"},
+		{"bt", "-game", "../../pkg/board1/testdata/star-battle.json", "-stars", "2", "-all"},
+	} {
+		if err := run(args); err != nil {
+			t.Errorf("%v: %v", args[1:], err)
+		}
+	}
+}
+
+func TestAdjacency(t *testing.T) {
+	args := []string{"bt", "-game", "../../2025-04-24.txt", "-adjacency", "orthogonal", "-all"}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+	args = []string{"bt", "-game", "../../2025-04-24.txt", "-adjacency", "bishop"}
+	if err := run(args); err == nil {
+		t.Error("no error for adjacency bishop")
+	}
+}
+
+func TestNQueens(t *testing.T) {
+	for _, args := range [][]string{
+		{"bt", "nqueens", "-size", "8", "-color", "plain"},
+		{"bt", "nqueens", "-size", "8", "-count", "-solver", "parallel"},
+		{"bt", "-game", "../../2025-04-24.txt", "-diagonals", "-all"},
+	} {
+		if err := run(args); err != nil {
+			t.Errorf("%v: %v", args[1:], err)
+		}
+	}
+	if err := run([]string{"bt", "nqueens", "-size", "3"}); !errors.Is(err, board1.ErrNoSolution) {
+		t.Errorf("got error %v, want %v", err, board1.ErrNoSolution)
+	}
+}
+
+func TestColor(t *testing.T) {
+	args := []string{"bt", "-game", "../../2025-04-23.txt", "-color", "truecolor", "-hideblocked"}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+
+	args = []string{"bt", "hint", "-game", "../../2025-04-23.txt", "-color", "rainbow"}
+	if err := run(args); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	args := []string{"bt", "check", "../../2025-4-22.txt", "../../2025-04-23.txt"}
+
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+
+	args = []string{"bt", "check", "-game", "board.txt"}
+	if err := run(args); !errors.Is(err, board1.ErrNotUnique) {
+		t.Errorf("got error %v, want %v", err, board1.ErrNotUnique)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	args := []string{"bt", "explain", "-game", "../../2025-04-24.txt"}
+
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHint(t *testing.T) {
+	position := filepath.Join(t.TempDir(), "position.txt")
+	args := []string{"bt", "hint", "-game", "../../2025-04-24.txt", "-o", position}
+	if err := run(args); err != nil {
+		t.Fatal(err)
+	}
+
+	// Continue from the written position.
+	args = []string{"bt", "hint", "-game", "../../2025-04-24.txt", "-position", position}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRate(t *testing.T) {
+	args := []string{"bt", "rate", "-sort", "../../2025-4-22.txt", "../../2025-04-23.txt", "../../2025-04-24.txt"}
+
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	game := filepath.Join(t.TempDir(), "game.txt")
+	args := []string{"bt", "generate", "-size", "8", "-seed", "1", "-difficulty", "hard", "-o", game}
+	if err := run(args); err != nil {
+		t.Fatal(err)
+	}
+
+	args = []string{"bt", "check", game}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	args := []string{"bt", "validate", "../../2025-4-22.txt", "board.txt"}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.txt")
+	if err := os.WriteFile(bad, []byte("0 0\n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args = []string{"bt", "validate", bad}
+	if err := run(args); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestShapes(t *testing.T) {
+	dir := t.TempDir()
+	game := filepath.Join(dir, "game.txt")
+	if err := os.WriteFile(game, []byte("..0011\n.00111\n222.13\n2.4433\n244..3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"bt", "validate", game},
+		{"bt", "-game", game, "-solver", "simple", "-color", "plain"},
+		{"bt", "-game", game, "-solver", "dlx", "-all"},
+		{"bt", "convert", "-json", "-o", filepath.Join(dir, "game.json"), game},
+		{"bt", "render", "-game", filepath.Join(dir, "game.json"), "-solve", "-o", filepath.Join(dir, "game.svg")},
+	} {
+		if err := run(args); err != nil {
+			t.Errorf("%v: %v", args[1:], err)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	game := filepath.Join(t.TempDir(), "game.json")
+	args := []string{"bt", "generate", "-size", "7", "-seed", "1", "-json", "-o", game}
+	if err := run(args); err != nil {
+		t.Fatal(err)
+	}
+
+	// The format is detected from the content.
+	for _, args := range [][]string{
+		{"bt", "validate", game},
+		{"bt", "check", game},
+		{"bt", "hint", "-game", game},
+		{"bt", "-game", game, "-json"},
+	} {
+		if err := run(args); err != nil {
+			t.Errorf("%v: %v", args[1:], err)
+		}
+	}
+}
+
+func TestConvertScreenshot(t *testing.T) {
+	// A 4x4 grid with an area per row.
+	const field = 30
+	img := image.NewRGBA(image.Rect(0, 0, 4*field+1, 4*field+1))
+	colours := []color.RGBA{{0xbb, 0xa3, 0xe2, 0xff}, {0xff, 0xc9, 0x92, 0xff}, {0x96, 0xbe, 0xff, 0xff}, {0xb3, 0xdf, 0xa0, 0xff}}
+	for y := range img.Bounds().Dy() {
+		for x := range img.Bounds().Dx() {
+			if x%field == 0 || y%field == 0 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, colours[y/field])
+			}
+		}
+	}
+	dir := t.TempDir()
+	shot := filepath.Join(dir, "shot.png")
+	f, err := os.Create(shot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	game := filepath.Join(dir, "game.txt")
+	args := []string{"bt", "convert", "-o", game, shot}
+	if err := run(args); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "0\t0\t0\t0\n1\t1\t1\t1\n2\t2\t2\t2\n3\t3\t3\t3\n"
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"game.svg", "game.png"} {
+		args := []string{"bt", "render", "-game", "../../2025-04-23.txt", "-solve", "-marks", "-o", filepath.Join(dir, name)}
+		if err := run(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The rendered puzzle can be read back as a screenshot.
+	args := []string{"bt", "check", filepath.Join(dir, "game.png")}
+	if err := run(args); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	g, err := loadGame("../../2025-04-23.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, _, err := g.Solve(&board1.BitSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := play.New(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Walk down the rows and put a crown on the queen of each.
+	var keys strings.Builder
+	for row := range g.Rows {
+		for col := range g.Cols {
+			if sol.Get(row, col) == board1.Queen {
+				keys.WriteString(strings.Repeat("l", col) + "\r" + strings.Repeat("h", col))
+			}
+		}
+		keys.WriteString("\x1b[B")
+	}
+	var out bytes.Buffer
+	if err := playGame(m, strings.NewReader(keys.String()), &out, board1.PlainText); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Solved() || !strings.Contains(out.String(), "solved in") {
+		t.Errorf("not solved:\n%s", out.String())
+	}
+}
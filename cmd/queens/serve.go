@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/myhops/queens/pkg/server"
+)
+
+// runServe serves the solver over HTTP.
+func runServe(args []string) error {
+	s := server.New()
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	fs.DurationVar(&s.Timeout, "timeout", s.Timeout, "longest time a request may take")
+	fs.Int64Var(&s.MaxBytes, "maxbytes", s.MaxBytes, "largest request body in bytes")
+	fs.IntVar(&s.MaxSize, "maxsize", s.MaxSize, "largest number of rows or columns of a board")
+	fs.IntVar(&s.MaxCount, "maxcount", s.MaxCount, "highest limit for counting solutions")
+	fs.IntVar(&s.Budget.Nodes, "nodes", 0, "largest number of search nodes of a request, 0 for no limit")
+	fs.Int64Var(&s.Budget.Placements, "placements", 0, "largest number of queens placed by a search, 0 for no limit")
+	fs.Parse(args[1:])
+
+	hs := &http.Server{
+		Addr:              *addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       s.Timeout,
+		WriteTimeout:      s.Timeout + 5*time.Second,
+	}
+	slog.Info("serving", "addr", *addr)
+	return hs.ListenAndServe()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// runValidate prints the issues of every game file.
+// Game files can be passed with -game or as arguments.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	gameFile := fs.String("game", "", "game file")
+	fs.Parse(args[1:])
+
+	files := fs.Args()
+	if *gameFile != "" {
+		files = append([]string{*gameFile}, files...)
+	}
+	if len(files) == 0 {
+		return errors.New("no game file given")
+	}
+
+	var errs []error
+	for _, f := range files {
+		_, err := loadPuzzle(f)
+		var ve *board1.ValidationError
+		switch {
+		case errors.As(err, &ve):
+			for _, is := range ve.Issues {
+				if is.Line == 0 {
+					fmt.Printf("%s: %s\n", f, is.Msg)
+				} else {
+					fmt.Printf("%s:%d:%d: %s\n", f, is.Line, is.Col, is.Msg)
+				}
+			}
+			errs = append(errs, fmt.Errorf("%s: %d issues", f, len(ve.Issues)))
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", f, err))
+		default:
+			fmt.Printf("%s: ok\n", f)
+		}
+	}
+	return errors.Join(errs...)
+}
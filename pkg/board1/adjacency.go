@@ -0,0 +1,80 @@
+package board1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Adjacency is the rule for the fields around a queen that cannot hold
+// another queen. Queens always block their row, column and area.
+type Adjacency int
+
+const (
+	// KingAdjacency blocks the 8 neighbours of a queen, the rule of Queens.
+	KingAdjacency Adjacency = iota
+	// OrthogonalAdjacency blocks the fields left, right, above and below
+	// a queen.
+	OrthogonalAdjacency
+	// NoAdjacency blocks no fields around a queen.
+	NoAdjacency
+	// KnightAdjacency blocks the fields a knight's move away from a queen.
+	KnightAdjacency
+)
+
+func (a Adjacency) String() string {
+	switch a {
+	case KingAdjacency:
+		return "king"
+	case OrthogonalAdjacency:
+		return "orthogonal"
+	case NoAdjacency:
+		return "none"
+	case KnightAdjacency:
+		return "knight"
+	default:
+		return "?"
+	}
+}
+
+// ParseAdjacency returns the adjacency rule with name s.
+func ParseAdjacency(s string) (Adjacency, error) {
+	for a := KingAdjacency; a <= KnightAdjacency; a++ {
+		if strings.EqualFold(s, a.String()) {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown adjacency %q", s)
+}
+
+// MarshalText stores a by name in JSON.
+func (a Adjacency) MarshalText() ([]byte, error) {
+	if a < KingAdjacency || a > KnightAdjacency {
+		return nil, fmt.Errorf("unknown adjacency %d", int(a))
+	}
+	return []byte(a.String()), nil
+}
+
+func (a *Adjacency) UnmarshalText(b []byte) error {
+	v, err := ParseAdjacency(string(b))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+var adjacencyOffsets = [...][]Position{
+	KingAdjacency:       {{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}},
+	OrthogonalAdjacency: {{-1, 0}, {0, -1}, {0, 1}, {1, 0}},
+	NoAdjacency:         nil,
+	KnightAdjacency:     {{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}},
+}
+
+// Offsets returns the row and column offsets of the fields a queen blocks.
+// The caller must not change them.
+func (a Adjacency) Offsets() []Position {
+	if a < KingAdjacency || a > KnightAdjacency {
+		return nil
+	}
+	return adjacencyOffsets[a]
+}
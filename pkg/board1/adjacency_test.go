@@ -0,0 +1,114 @@
+package board1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAdjacencyCountSolutions(t *testing.T) {
+	tests := []struct {
+		adjacency Adjacency
+		stars     int
+		size      int
+		want      int
+	}{
+		{adjacency: KingAdjacency, stars: 1, size: 6, want: 90},
+		// Rows and columns keep single queens apart orthogonally.
+		{adjacency: OrthogonalAdjacency, stars: 1, size: 5, want: 120},
+		{adjacency: NoAdjacency, stars: 1, size: 5, want: 120},
+		{adjacency: KnightAdjacency, stars: 1, size: 6, want: 94},
+		{adjacency: OrthogonalAdjacency, stars: 2, size: 5, want: 16},
+		{adjacency: NoAdjacency, stars: 2, size: 4, want: 90},
+		{adjacency: KnightAdjacency, stars: 2, size: 5, want: 6},
+	}
+	enumerators := []Enumerator{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &ParallelSolver{}}
+	for _, tt := range tests {
+		for _, e := range enumerators {
+			g := rowAreas(tt.size)
+			g.Stars = tt.stars
+			g.Adjacency = tt.adjacency
+			got, err := g.CountSolutions(e, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("%T: %s, %d stars, size %d: got %d solutions, want %d", e, tt.adjacency, tt.stars, tt.size, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestAdjacencyPlaceQueen(t *testing.T) {
+	g := rowAreas(5)
+	g.Stars = 2
+	g.Adjacency = KnightAdjacency
+	b := &Board{Fields: make([]State, 25), Rows: 5, Cols: 5}
+	if err := g.PlaceQueen(b, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if b.Get(0, 1) != Blocked || b.Get(3, 4) != Blocked || b.Get(1, 1) != Empty || b.Get(2, 3) != Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+
+	bg, err := NewBitGame(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bb, err := bg.PlaceQueen(BitBoard{}, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for row := range 5 {
+		for col := range 5 {
+			if bb.Get(bg, row, col) != b.Get(row, col) {
+				t.Errorf("bit board has %s on (%d, %d), want %s", bb.Get(bg, row, col), row, col, b.Get(row, col))
+			}
+		}
+	}
+}
+
+func TestParseAdjacency(t *testing.T) {
+	for a := KingAdjacency; a <= KnightAdjacency; a++ {
+		got, err := ParseAdjacency(strings.ToUpper(a.String()))
+		if err != nil || got != a {
+			t.Errorf("got %v, %v, want %v", got, err, a)
+		}
+	}
+	if _, err := ParseAdjacency("bishop"); err == nil {
+		t.Error("no error for bishop")
+	}
+}
+
+func TestPuzzleAdjacency(t *testing.T) {
+	const input = `{"size":{"rows":4,"cols":4},"areas":[
+{"label":"a","cells":[[0,0],[0,1],[0,2],[0,3]]},
+{"label":"b","cells":[[1,0],[1,1],[1,2],[1,3]]},
+{"label":"c","cells":[[2,0],[2,1],[2,2],[2,3]]},
+{"label":"d","cells":[[3,0],[3,1],[3,2],[3,3]]}],
+"adjacency":"none","solution":[[0,0],[1,1],[2,2],[3,3]]}`
+	p, err := LoadPuzzle(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := p.Game(); g.Adjacency != NoAdjacency {
+		t.Errorf("got adjacency %s, want none", g.Adjacency)
+	}
+	var sb strings.Builder
+	if err := EncodePuzzle(&sb, p); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), `"adjacency": "none"`) {
+		t.Errorf("adjacency not encoded:\n%s", sb.String())
+	}
+
+	// The diagonal solution is not valid with king adjacency.
+	_, err = LoadPuzzle(strings.NewReader(strings.Replace(input, `"none"`, `"king"`, 1)))
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Issues) != 2 || ve.Issues[0].Msg != "solution queen (2, 2) is blocked by another queen" {
+		t.Errorf("got error %v", err)
+	}
+	if _, err := LoadPuzzle(strings.NewReader(strings.Replace(input, `"none"`, `"bishop"`, 1))); err == nil {
+		t.Error("no error for adjacency bishop")
+	}
+}
@@ -0,0 +1,156 @@
+package board1
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode is the kind of colour codes a Renderer writes.
+type ColorMode int
+
+const (
+	// PlainText writes the board like Board.Print.
+	PlainText ColorMode = iota
+	// Color256 uses the ANSI 256 colour palette.
+	Color256
+	// TrueColor uses 24 bit ANSI colours.
+	TrueColor
+)
+
+func (m ColorMode) String() string {
+	switch m {
+	case PlainText:
+		return "plain"
+	case Color256:
+		return "256"
+	case TrueColor:
+		return "truecolor"
+	default:
+		return "?"
+	}
+}
+
+// ParseColorMode returns the mode with name s.
+func ParseColorMode(s string) (ColorMode, error) {
+	for m := PlainText; m <= TrueColor; m++ {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown color mode %q", s)
+}
+
+// DetectColorMode returns the best mode for f. It is PlainText when f is
+// not a terminal or NO_COLOR is set, and TrueColor when COLORTERM says
+// the terminal supports it.
+func DetectColorMode(f *os.File) ColorMode {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 || os.Getenv("NO_COLOR") != "" {
+		return PlainText
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	return Color256
+}
+
+// areaPalette holds the colours of areas without a Color, close to the
+// colours of the LinkedIn game.
+var areaPalette = []string{
+	"#bba3e2", "#ffc992", "#96beff", "#b3dfa0", "#dfdfdf", "#ff7b60",
+	"#e6f388", "#b9b29e", "#dfa0bf", "#a3d2d8", "#62efea", "#ff93f3",
+}
+
+// AreaColor returns the colour of area i of g, as "#rrggbb".
+func (g *Game) AreaColor(i int) string {
+	if c := g.Areas[i].Color; c != "" {
+		return c
+	}
+	return areaPalette[i%len(areaPalette)]
+}
+
+// Renderer writes a board with the colours of the areas of its game.
+type Renderer struct {
+	Mode ColorMode
+	// HideBlocked leaves blocked fields empty.
+	HideBlocked bool
+}
+
+// Render writes b, a board of g, to w. Queens are shown as crowns.
+func (r Renderer) Render(w io.Writer, g *Game, b *Board) error {
+	// area holds the area of every field, -1 if it has none.
+	area := make([]int, b.Rows*b.Cols)
+	for i := range area {
+		area[i] = -1
+	}
+	for i, a := range g.Areas {
+		for _, p := range a.Cells {
+			if p[0] >= 0 && p[0] < b.Rows && p[1] >= 0 && p[1] < b.Cols {
+				area[p[0]*b.Cols+p[1]] = i
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for row := range b.Rows {
+		for col := range b.Cols {
+			s := b.Get(row, col)
+			if s == Blocked && r.HideBlocked {
+				s = Empty
+			}
+			if r.Mode == PlainText {
+				bw.WriteString(s.String())
+				continue
+			}
+			a := area[row*b.Cols+col]
+			if a >= 0 {
+				bw.WriteString(r.Background(g.AreaColor(a)))
+			}
+			switch s {
+			case Queen:
+				bw.WriteString(" ♛ ")
+			case Blocked:
+				bw.WriteString(" × ")
+			default:
+				bw.WriteString("   ")
+			}
+			if a >= 0 {
+				bw.WriteString("\x1b[0m")
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Background returns the escape code for black text on colour c,
+// an empty string in PlainText mode.
+func (r Renderer) Background(c string) string {
+	if r.Mode == PlainText {
+		return ""
+	}
+	var red, green, blue int
+	if _, err := fmt.Sscanf(c, "#%02x%02x%02x", &red, &green, &blue); err != nil {
+		return ""
+	}
+	if r.Mode == TrueColor {
+		return fmt.Sprintf("\x1b[30;48;2;%d;%d;%dm", red, green, blue)
+	}
+	return fmt.Sprintf("\x1b[30;48;5;%dm", 16+36*cubeLevel(red)+6*cubeLevel(green)+cubeLevel(blue))
+}
+
+// cubeLevel returns the closest of the six levels of the 256 colour cube.
+func cubeLevel(v int) int {
+	levels := []int{0, 95, 135, 175, 215, 255}
+	best := 0
+	for i, l := range levels {
+		if abs(v-l) < abs(v-levels[best]) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,85 @@
+package board1
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	g := NewGame(2, 2,
+		Area{Label: "a", Color: "#ff7b60", Cells: []Position{{0, 0}, {0, 1}}},
+		NewArea("b", Position{1, 0}, Position{1, 1}),
+	)
+	b := g.BoardPool.Get()
+	if err := g.PlaceQueen(b, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		r        Renderer
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "plain",
+			r:        Renderer{},
+			contains: []string{"QX\nXX\n"},
+			excludes: []string{"\x1b"},
+		},
+		{
+			name:     "plain hide blocked",
+			r:        Renderer{HideBlocked: true},
+			contains: []string{"Q \n  \n"},
+		},
+		{
+			name:     "truecolor",
+			r:        Renderer{Mode: TrueColor},
+			contains: []string{"\x1b[30;48;2;255;123;96m ♛ \x1b[0m", "\x1b[30;48;2;255;201;146m × "},
+		},
+		{
+			name:     "256",
+			r:        Renderer{Mode: Color256, HideBlocked: true},
+			contains: []string{"\x1b[30;48;5;209m ♛ ", "\x1b[30;48;5;222m   "},
+			excludes: []string{"×"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.r.Render(&buf, g, b); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("output %q does not contain %q", buf.String(), s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("output %q contains %q", buf.String(), s)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectColorMode(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if m := DetectColorMode(f); m != PlainText {
+		t.Errorf("got mode %v for a file, want %v", m, PlainText)
+	}
+
+	for m := PlainText; m <= TrueColor; m++ {
+		if got, err := ParseColorMode(m.String()); err != nil || got != m {
+			t.Errorf("ParseColorMode(%q) = %v, %v", m.String(), got, err)
+		}
+	}
+}
@@ -1,11 +1,30 @@
 package board1
 
-import "slices"
+import (
+	"fmt"
+	"slices"
+)
 
-type Area []Position
+// Area is a group of fields that must hold one queen.
+type Area struct {
+	// Label identifies the area, it is the symbol of the area in the
+	// puzzle file.
+	Label string `json:"label"`
+	// Color is the optional colour of the area, like "#ffc992".
+	Color string     `json:"color,omitempty"`
+	Cells []Position `json:"cells"`
+}
+
+func NewArea(label string, cells ...Position) Area {
+	return Area{Label: label, Cells: cells}
+}
 
 func (a Area) Contains(row, col int) bool {
-	for _, pos := range a {
+	return containsField(a.Cells, row, col)
+}
+
+func containsField(cells []Position, row, col int) bool {
+	for _, pos := range cells {
 		if pos[0] == row && pos[1] == col {
 			return true
 		}
@@ -13,9 +32,75 @@ func (a Area) Contains(row, col int) bool {
 	return false
 }
 
+// SortAreasReverse sorts the areas from large to small.
+// Areas of the same size keep their order.
 func SortAreasReverse(areas []Area) {
-	slices.SortFunc(areas, func(a, b Area) int {
-		return len(b) - len(a)
+	slices.SortStableFunc(areas, func(a, b Area) int {
+		return len(b.Cells) - len(a.Cells)
 	})
 }
 
+// areaName returns the name of area i of areas, by label if it has one.
+func areaName(areas []Area, i int) string {
+	if areas[i].Label != "" {
+		return fmt.Sprintf("area %s", areas[i].Label)
+	}
+	return fmt.Sprintf("area %d", i+1)
+}
+
+// regions returns the areas of g. A game without areas, like the chess
+// N-queens puzzle, has every line of the shorter dimension without its
+// holes as an area: the rows, or the columns of a board that is higher
+// than wide. Lines with only holes are skipped.
+func (g *Game) regions() []Area {
+	if len(g.Areas) > 0 {
+		return g.Areas
+	}
+	hole := g.holeMask()
+	rows, cols := g.lines()
+	var areas []Area
+	if rows <= cols {
+		for row := range g.Rows {
+			var a Area
+			for col := range g.Cols {
+				if !hole[row*g.Cols+col] {
+					a.Cells = append(a.Cells, Position{row, col})
+				}
+			}
+			if len(a.Cells) > 0 {
+				areas = append(areas, a)
+			}
+		}
+		return areas
+	}
+	for col := range g.Cols {
+		var a Area
+		for row := range g.Rows {
+			if !hole[row*g.Cols+col] {
+				a.Cells = append(a.Cells, Position{row, col})
+			}
+		}
+		if len(a.Cells) > 0 {
+			areas = append(areas, a)
+		}
+	}
+	return areas
+}
+
+// Area returns the area with label.
+func (g *Game) Area(label string) (Area, bool) {
+	for _, a := range g.Areas {
+		if a.Label == label {
+			return a, true
+		}
+	}
+	return Area{}, false
+}
+
+// sortedAreas returns the areas of g from large to small,
+// without changing the order of g.Areas.
+func (g *Game) sortedAreas() []Area {
+	areas := slices.Clone(g.regions())
+	SortAreasReverse(areas)
+	return areas
+}
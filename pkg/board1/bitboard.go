@@ -0,0 +1,341 @@
+package board1
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// MaxBitFields is the largest number of fields a BitBoard can hold.
+const MaxBitFields = 256
+
+// bitset is a set of fields, field row, col has index row*cols+col.
+type bitset [MaxBitFields / 64]uint64
+
+func (s *bitset) set(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s bitset) has(i int) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+func (s bitset) or(o bitset) bitset {
+	for i := range s {
+		s[i] |= o[i]
+	}
+	return s
+}
+
+func (s bitset) and(o bitset) bitset {
+	for i := range s {
+		s[i] &= o[i]
+	}
+	return s
+}
+
+func (s bitset) andNot(o bitset) bitset {
+	for i := range s {
+		s[i] &^= o[i]
+	}
+	return s
+}
+
+func (s bitset) count() int {
+	var n int
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// from returns the fields of s from field i on.
+func (s bitset) from(i int) bitset {
+	for k := range s {
+		switch {
+		case (k+1)*64 <= i:
+			s[k] = 0
+		case k*64 < i:
+			s[k] &^= 1<<(i%64) - 1
+		}
+	}
+	return s
+}
+
+// last returns the last field in s, -1 if s is empty.
+func (s bitset) last() int {
+	for k := len(s) - 1; k >= 0; k-- {
+		if s[k] != 0 {
+			return k*64 + 63 - bits.LeadingZeros64(s[k])
+		}
+	}
+	return -1
+}
+
+// each calls f for every field in s until f returns false.
+func (s bitset) each(f func(i int) bool) bool {
+	for k, w := range s {
+		for w != 0 {
+			if !f(k*64 + bits.TrailingZeros64(w)) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+// BitGame holds the precomputed masks of a game.
+type BitGame struct {
+	Rows  int
+	Cols  int
+	Stars int
+
+	rows []bitset
+	cols []bitset
+	// areas holds the areas, the rows if the game has none.
+	areas []bitset
+	// owner holds the area of every field, -1 if none.
+	owner []int
+	// block holds for every field the fields that a queen on that field
+	// blocks, the field itself included. With more stars these are only
+	// the fields around it, full units are blocked by placeQueen.
+	block []bitset
+}
+
+// NewBitGame computes the masks of g.
+// It fails if g has more than MaxBitFields fields.
+func NewBitGame(g *Game) (*BitGame, error) {
+	if g.Rows*g.Cols > MaxBitFields {
+		return nil, fmt.Errorf("board of %dx%d has more than %d fields", g.Rows, g.Cols, MaxBitFields)
+	}
+	bg := &BitGame{
+		Rows:  g.Rows,
+		Cols:  g.Cols,
+		Stars: g.stars(),
+		rows:  make([]bitset, g.Rows),
+		cols:  make([]bitset, g.Cols),
+		areas: make([]bitset, len(g.regions())),
+		block: make([]bitset, g.Rows*g.Cols),
+	}
+	for row := range g.Rows {
+		for col := range g.Cols {
+			bg.rows[row].set(bg.field(row, col))
+			bg.cols[col].set(bg.field(row, col))
+		}
+	}
+	owner := make([]int, g.Rows*g.Cols)
+	bg.owner = owner
+	for i := range owner {
+		owner[i] = -1
+	}
+	regions := g.regions()
+	for a, area := range regions {
+		for _, p := range area.Cells {
+			if p[0] < 0 || p[0] >= g.Rows || p[1] < 0 || p[1] >= g.Cols {
+				return nil, fmt.Errorf("position (%d, %d) of %s is outside the board", p[0], p[1], areaName(regions, a))
+			}
+			bg.areas[a].set(bg.field(p[0], p[1]))
+			owner[bg.field(p[0], p[1])] = a
+		}
+	}
+	for row := range g.Rows {
+		for col := range g.Cols {
+			f := bg.field(row, col)
+			var m bitset
+			if bg.Stars == 1 {
+				m = bg.rows[row].or(bg.cols[col])
+				if owner[f] >= 0 {
+					m = m.or(bg.areas[owner[f]])
+				}
+			}
+			m.set(f)
+			for _, o := range g.Adjacency.Offsets() {
+				i, j := row+o[0], col+o[1]
+				if i >= 0 && i < g.Rows && j >= 0 && j < g.Cols {
+					m.set(bg.field(i, j))
+				}
+			}
+			if g.Diagonals {
+				for _, d := range []Position{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+					for i, j := row+d[0], col+d[1]; i >= 0 && i < g.Rows && j >= 0 && j < g.Cols; i, j = i+d[0], j+d[1] {
+						m.set(bg.field(i, j))
+					}
+				}
+			}
+			bg.block[f] = m
+		}
+	}
+	return bg, nil
+}
+
+func (bg *BitGame) field(row, col int) int {
+	return row*bg.Cols + col
+}
+
+// BitBoard is a board stored as bitsets. It is a value,
+// copying it does not allocate.
+type BitBoard struct {
+	queens  bitset
+	blocked bitset
+}
+
+func (b BitBoard) Get(bg *BitGame, row, col int) State {
+	f := bg.field(row, col)
+	switch {
+	case b.queens.has(f):
+		return Queen
+	case b.blocked.has(f):
+		return Blocked
+	default:
+		return Empty
+	}
+}
+
+// PlaceQueen returns b with a queen on row, col and the fields it blocks.
+func (bg *BitGame) PlaceQueen(b BitBoard, row, col int) (BitBoard, error) {
+	if row < 0 || row >= bg.Rows || col < 0 || col >= bg.Cols {
+		panic("index out of range")
+	}
+	if s := b.Get(bg, row, col); s != Empty {
+		return b, fmt.Errorf("position (%d, %d) is occupied with %s", row, col, s.String())
+	}
+	return bg.placeQueen(b, bg.field(row, col)), nil
+}
+
+func (bg *BitGame) placeQueen(b BitBoard, f int) BitBoard {
+	b.queens.set(f)
+	b.blocked = b.blocked.or(bg.block[f])
+	if bg.Stars > 1 {
+		b.blockFull(bg.Stars, bg.rows[f/bg.Cols])
+		b.blockFull(bg.Stars, bg.cols[f%bg.Cols])
+		if a := bg.owner[f]; a >= 0 {
+			b.blockFull(bg.Stars, bg.areas[a])
+		}
+	}
+	return b
+}
+
+// blockFull blocks unit when it holds stars queens.
+func (b *BitBoard) blockFull(stars int, unit bitset) {
+	if b.queens.and(unit).count() == stars {
+		b.blocked = b.blocked.or(unit)
+	}
+}
+
+// Board converts b to a Board from the pool of g.
+func (bg *BitGame) Board(g *Game, b BitBoard) (*Board, error) {
+	nb := g.NewBoard()
+	var err error
+	b.queens.each(func(f int) bool {
+		err = g.PlaceQueen(nb, f/bg.Cols, f%bg.Cols)
+		return err == nil
+	})
+	if err != nil {
+		g.BoardPool.Put(nb)
+		return nil, err
+	}
+	return nb, nil
+}
+
+// BitSolver searches on a BitBoard. It always continues with the area
+// that has the fewest empty fields, and backtracks as soon as an area
+// has fewer empty fields left than queens it needs.
+type BitSolver struct{}
+
+func (s *BitSolver) Solve(sr *Search, g *Game) (*Board, error) {
+	var res *Board
+	err := s.search(sr, g, func(bg *BitGame, b BitBoard) (bool, error) {
+		nb, err := bg.Board(g, b)
+		res = nb
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrNoSolution
+	}
+	return res, nil
+}
+
+func (s *BitSolver) Enumerate(sr *Search, g *Game, yield func(*Board) bool) error {
+	return s.search(sr, g, func(bg *BitGame, b BitBoard) (bool, error) {
+		nb, err := bg.Board(g, b)
+		if err != nil {
+			return false, err
+		}
+		res := nb.Clone()
+		g.BoardPool.Put(nb)
+		return yield(res), nil
+	})
+}
+
+// Count counts the solutions without converting them to boards.
+func (s *BitSolver) Count(sr *Search, g *Game, limit int) (int, error) {
+	var n int
+	err := s.search(sr, g, func(*BitGame, BitBoard) (bool, error) {
+		n++
+		return limit <= 0 || n < limit, nil
+	})
+	return n, err
+}
+
+func (s *BitSolver) search(sr *Search, g *Game, found func(*BitGame, BitBoard) (bool, error)) error {
+	sr.setPhase("prepare")
+	bg, err := NewBitGame(g)
+	if err != nil {
+		return err
+	}
+	sr.setPhase("search")
+	_, err = s.solveBoard(sr, bg, BitBoard{}, len(bg.areas)*bg.Stars, found)
+	return err
+}
+
+// solveBoard returns false when found asked to stop.
+func (s *BitSolver) solveBoard(sr *Search, bg *BitGame, b BitBoard, n int, found func(*BitGame, BitBoard) (bool, error)) (bool, error) {
+	if n == 0 {
+		return found(bg, b)
+	}
+	if err := sr.enter(len(bg.areas)*bg.Stars - n); err != nil {
+		return false, err
+	}
+
+	// Find the open area with the fewest empty fields. The queens of an
+	// area are placed in field order, so only the fields after its last
+	// queen are empty for the search.
+	best, bestCount := -1, 0
+	var bestEmpty bitset
+	for a, m := range bg.areas {
+		need := bg.Stars
+		empty := m.andNot(b.blocked)
+		if need == 1 {
+			// A queen blocks its area, an area with empty fields has none.
+			if empty == (bitset{}) && m.and(b.queens) != (bitset{}) {
+				continue
+			}
+		} else if queens := m.and(b.queens); queens != (bitset{}) {
+			need -= queens.count()
+			if need <= 0 {
+				continue
+			}
+			empty = empty.from(queens.last() + 1)
+		}
+		c := empty.count()
+		if c < need {
+			sr.backtrack()
+			return true, nil
+		}
+		if best < 0 || c < bestCount {
+			best, bestCount, bestEmpty = a, c, empty
+		}
+	}
+
+	more := true
+	var err error
+	bestEmpty.each(func(f int) bool {
+		sr.place()
+		more, err = s.solveBoard(sr, bg, bg.placeQueen(b, f), n-1, found)
+		return more && err == nil
+	})
+	return more, err
+}
@@ -0,0 +1,97 @@
+package board1
+
+import (
+	"os"
+	"testing"
+)
+
+func TestBitSolverCount(t *testing.T) {
+	for n := 4; n <= 7; n++ {
+		want, err := rowAreas(n).CountSolutions(&AreaSolver{}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := rowAreas(n).CountSolutions(&BitSolver{}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("size %d: got %d solutions, want %d", n, got, want)
+		}
+	}
+}
+
+func TestBitSolver(t *testing.T) {
+	for _, name := range []string{"../../2025-4-22.txt", "../../2025-04-23.txt", "../../2025-04-24.txt"} {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			a, i, err := LoadAreas(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g := NewGame(i, i, a...)
+			want, _, err := g.Solve(&AreaSolver{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, stats, err := g.Solve(&BitSolver{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range want.Fields {
+				if got.Fields[i] != want.Fields[i] {
+					got.Print()
+					t.Fatal("solutions differ")
+				}
+			}
+			t.Logf("stats:\n%v", stats)
+		})
+	}
+}
+
+func TestNewBitGameTooLarge(t *testing.T) {
+	if _, err := NewBitGame(rowAreas(17)); err == nil {
+		t.Error("expected an error for a 17x17 board")
+	}
+}
+
+func benchmarkSolver(b *testing.B, s Solver) {
+	f, err := os.Open("../../2025-04-23.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+	a, i, err := LoadAreas(f)
+	if err != nil {
+		b.Fatal(err)
+	}
+	g := NewGame(i, i, a...)
+
+	b.ReportAllocs()
+	for range b.N {
+		if _, _, err := g.Solve(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBitSolver(b *testing.B) {
+	benchmarkSolver(b, &BitSolver{})
+}
+
+func BenchmarkAreaSolver(b *testing.B) {
+	benchmarkSolver(b, &AreaSolver{})
+}
+
+func BenchmarkDLXSolver(b *testing.B) {
+	benchmarkSolver(b, &DLXSolver{})
+}
+
+func BenchmarkParallelSolver(b *testing.B) {
+	benchmarkSolver(b, &ParallelSolver{})
+}
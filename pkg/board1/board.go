@@ -1,9 +1,11 @@
 package board1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 type State int
@@ -12,6 +14,9 @@ const (
 	Empty State = iota
 	Blocked
 	Queen
+	// Hole is a field that is not part of the board. It belongs to no
+	// area and never holds a queen.
+	Hole
 )
 
 func (s State) String() string {
@@ -22,6 +27,8 @@ func (s State) String() string {
 		return "X"
 	case Queen:
 		return "Q"
+	case Hole:
+		return "."
 	default:
 		return "?"
 	}
@@ -32,7 +39,9 @@ type BoardPool struct {
 	rows int
 	cols int
 
-	maxEntries int
+	// maxEntries counts the boards created, the pool may be
+	// used by several goroutines.
+	maxEntries atomic.Int64
 }
 
 func NewBoardPool(rows, cols int) *BoardPool {
@@ -59,7 +68,7 @@ func (p *BoardPool) New() any {
 		Rows:   p.rows,
 		Cols:   p.cols,
 	}
-	p.maxEntries++
+	p.maxEntries.Add(1)
 	return b
 }
 
@@ -74,18 +83,26 @@ func (p *BoardPool) Put(b *Board) {
 }
 
 func (p *BoardPool) MaxEntries() int {
-	return p.maxEntries
+	return int(p.maxEntries.Load())
 }
 
 type Game struct {
 	Areas []Area
 	Rows  int
 	Cols  int
+	// Stars is the number of queens per row, column and area, 1 if 0.
+	// Star Battle puzzles have 2 or 3.
+	Stars int
+	// Adjacency is the rule for the fields a queen blocks around it.
+	Adjacency Adjacency
+	// Diagonals makes queens attack along their full diagonals too, like
+	// chess queens.
+	Diagonals bool
+	// Holes are the fields that are not part of the board, they belong
+	// to no area. Boards from NewBoard have them as Hole.
+	Holes []Position
 
 	BoardPool *BoardPool
-
-	solveCalled int
-	queenPlaced int64
 }
 
 func NewGame(rows, cols int, areas ...Area) *Game {
@@ -97,6 +114,28 @@ func NewGame(rows, cols int, areas ...Area) *Game {
 	}
 }
 
+// NQueens returns the chess N-queens puzzle: a board of n x n without
+// areas where queens attack along rows, columns and diagonals.
+func NQueens(n int) *Game {
+	g := NewGame(n, n)
+	g.Diagonals = true
+	return g
+}
+
+// NewBoard returns an empty board from the BoardPool with the holes of g.
+func (g *Game) NewBoard() *Board {
+	b := g.BoardPool.Get()
+	g.putHoles(b)
+	return b
+}
+
+// putHoles marks the holes of g on b.
+func (g *Game) putHoles(b *Board) {
+	for _, h := range g.Holes {
+		b.Put(h[0], h[1], Hole)
+	}
+}
+
 type Board struct {
 	Fields []State
 	Rows   int
@@ -122,36 +161,136 @@ func (b *Board) Get(row, col int) State {
 type Position []int // row, col
 
 func (g *Game) PlaceQueen(b *Board, row, col int) error {
-	g.queenPlaced++
 	if b.Get(row, col) != Empty {
 		return fmt.Errorf("position (%d, %d) is occupied with %s", row, col, b.Get(row, col).String())
 	}
+	g.putQueen(b, row, col)
+	return nil
+}
+
+// putQueen puts a queen on row, col and blocks all fields it attacks:
+// the fields around it by the adjacency rule, its diagonals if the game
+// has Diagonals, and its row, column and area when they are full.
+func (g *Game) putQueen(b *Board, row, col int) {
 	b.Put(row, col, Queen)
-	b.blockAround(row, col)
-	b.blockRow(row)
-	b.blockColumn(col)
+	b.blockAround(row, col, g.Adjacency.Offsets())
+	if g.Diagonals {
+		b.blockDiagonals(row, col)
+	}
+	k := g.stars()
+	if k == 1 || b.queensInRow(row) == k {
+		b.blockRow(row)
+	}
+	if k == 1 || b.queensInColumn(col) == k {
+		b.blockColumn(col)
+	}
 
-	if a := g.inArea(row, col); a != nil {
+	if a, ok := g.inArea(row, col); ok && (k == 1 || b.queensIn(a.Cells) == k) {
 		b.blockArea(a)
 	}
-	return nil
 }
 
-func (g *Game) QueenPlaced() int64 {
-	return g.queenPlaced
+// stars returns the number of queens per unit.
+func (g *Game) stars() int {
+	return max(g.Stars, 1)
+}
+
+// Queens returns the number of queens of a solution: stars for every
+// area, or for every line of the shorter dimension if g has no areas.
+// Rows and columns with only holes do not count.
+func (g *Game) Queens() int {
+	if len(g.Areas) > 0 {
+		return len(g.Areas) * g.stars()
+	}
+	rows, cols := g.lines()
+	return min(rows, cols) * g.stars()
+}
+
+// exactLines reports whether every row and every column must hold stars
+// queens. That is the case if there are as many rows or columns with
+// fields as areas. Otherwise, like the columns of a board that is wider
+// than high, they hold at most stars queens.
+func (g *Game) exactLines() (rows, cols bool) {
+	r, c := g.lines()
+	n := g.Queens() / g.stars()
+	return r == n, c == n
+}
+
+// lines returns the number of rows and columns that have fields that are
+// no hole.
+func (g *Game) lines() (rows, cols int) {
+	hole := g.holeMask()
+	for row := range g.Rows {
+		for col := range g.Cols {
+			if !hole[row*g.Cols+col] {
+				rows++
+				break
+			}
+		}
+	}
+	for col := range g.Cols {
+		for row := range g.Rows {
+			if !hole[row*g.Cols+col] {
+				cols++
+				break
+			}
+		}
+	}
+	return rows, cols
+}
+
+// holeMask returns for every field whether it is a hole.
+func (g *Game) holeMask() []bool {
+	hole := make([]bool, g.Rows*g.Cols)
+	for _, h := range g.Holes {
+		if h[0] >= 0 && h[0] < g.Rows && h[1] >= 0 && h[1] < g.Cols {
+			hole[h[0]*g.Cols+h[1]] = true
+		}
+	}
+	return hole
+}
+
+func (b *Board) queensInRow(row int) int {
+	var n int
+	for col := range b.Cols {
+		if b.Get(row, col) == Queen {
+			n++
+		}
+	}
+	return n
 }
 
-func (g *Game) inArea(row, col int) Area {
+func (b *Board) queensInColumn(col int) int {
+	var n int
+	for row := range b.Rows {
+		if b.Get(row, col) == Queen {
+			n++
+		}
+	}
+	return n
+}
+
+func (b *Board) queensIn(cells []Position) int {
+	var n int
+	for _, p := range cells {
+		if b.Get(p[0], p[1]) == Queen {
+			n++
+		}
+	}
+	return n
+}
+
+func (g *Game) inArea(row, col int) (Area, bool) {
 	for _, area := range g.Areas {
 		if area.Contains(row, col) {
-			return area
+			return area, true
 		}
 	}
-	return nil
+	return Area{}, false
 }
 
 func (b *Board) blockArea(area Area) {
-	for _, pos := range area {
+	for _, pos := range area.Cells {
 		x, y := pos[0], pos[1]
 		if b.Get(x, y) == Empty {
 			b.Put(x, y, Blocked)
@@ -159,9 +298,21 @@ func (b *Board) blockArea(area Area) {
 	}
 }
 
-func (b *Board) blockAround(row, col int) {
-	for i := max(row-1, 0); i < min(row+2, b.Rows); i++ {
-		for j := max(col-1, 0); j < min(col+2, b.Cols); j++ {
+// blockAround blocks the empty fields at offsets from row, col.
+func (b *Board) blockAround(row, col int, offsets []Position) {
+	for _, o := range offsets {
+		i, j := row+o[0], col+o[1]
+		if i >= 0 && i < b.Rows && j >= 0 && j < b.Cols && b.Get(i, j) == Empty {
+			b.Put(i, j, Blocked)
+		}
+	}
+}
+
+// blockDiagonals blocks the empty fields on both diagonals through
+// row, col.
+func (b *Board) blockDiagonals(row, col int) {
+	for _, d := range []Position{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+		for i, j := row+d[0], col+d[1]; i >= 0 && i < b.Rows && j >= 0 && j < b.Cols; i, j = i+d[0], j+d[1] {
 			if b.Get(i, j) == Empty {
 				b.Put(i, j, Blocked)
 			}
@@ -202,6 +353,8 @@ func (b *Board) FindEmpty(row, col int) (int, int, error) {
 				return i, j, nil
 			}
 		}
+		// next rows start at the first column
+		col = 0
 	}
 	return 0, 0, ErrNoEmptyFound
 }
@@ -243,15 +396,14 @@ func (g *Game) PlaceQueens(b *Board, queens []Position) {
 }
 
 type Solver interface {
-	Solve(g *Game) (*Board, error)
+	// Solve searches a solution of g. It counts its work in s, and stops
+	// with the error of s.enter.
+	Solve(s *Search, g *Game) (*Board, error)
 }
 
-func (g *Game) Solve(solver Solver) (*Board, error) {
-	g.solveCalled = 0
-	b := g.BoardPool.Get()
-	defer g.BoardPool.Put(b)
-
-	return solver.Solve(g)
+// Solve solves g with solver and returns the counts of the search.
+func (g *Game) Solve(solver Solver) (*Board, SolveStats, error) {
+	return g.SolveContext(context.Background(), solver, Budget{})
 }
 
 func (b *Board) CopyFrom(bc *Board) {
@@ -260,8 +412,16 @@ func (b *Board) CopyFrom(bc *Board) {
 	b.Cols = bc.Cols
 }
 
-func (g *Game) SolveCalled() int {
-	return g.solveCalled
+// Clone returns a copy of the board that does not belong to a BoardPool.
+// It is safe to keep the clone after the solver has finished.
+func (b *Board) Clone() *Board {
+	nb := &Board{
+		Fields: make([]State, len(b.Fields)),
+		Rows:   b.Rows,
+		Cols:   b.Cols,
+	}
+	copy(nb.Fields, b.Fields)
+	return nb
 }
 
 var ErrNoSolution = errors.New("no solution found")
@@ -270,7 +430,6 @@ func (g *Game) solveBoard(b *Board, n int) (*Board, error) {
 	if n == 0 {
 		return b, nil
 	}
-	g.solveCalled++
 
 	for row, col, err := b.FindEmpty(0, 0); err == nil; row, col, err = b.FindNextEmpty(row, col) {
 		// Create a new board
@@ -0,0 +1,68 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrBudget is returned when a search used up its Budget.
+var ErrBudget = errors.New("search budget used up")
+
+// Budget limits a search. A zero field is no limit.
+type Budget struct {
+	// Nodes is the largest number of search nodes.
+	Nodes int
+	// Placements is the largest number of queens placed by the search.
+	Placements int64
+}
+
+// StoppedError is returned when a search stopped before it finished.
+// It holds the counts of the search up to then.
+type StoppedError struct {
+	// Err is the error of the context, or ErrBudget.
+	Err   error
+	Stats SolveStats
+}
+
+func (e *StoppedError) Error() string {
+	return fmt.Sprintf("search stopped after %d nodes and %d queens placed: %v", e.Stats.Nodes, e.Stats.Placements, e.Err)
+}
+
+func (e *StoppedError) Unwrap() error {
+	return e.Err
+}
+
+// SolveContext solves g like Solve, but stops when ctx is done or the
+// search used up budget. The error is then a *StoppedError that wraps
+// ctx.Err() or ErrBudget.
+func (g *Game) SolveContext(ctx context.Context, solver Solver, budget Budget) (*Board, SolveStats, error) {
+	s := NewSearch(ctx, budget).bind(g)
+	if err := ctx.Err(); err != nil {
+		return nil, s.Stats(), s.stopped(err)
+	}
+	b, err := solver.Solve(s, g)
+	s.finish()
+	return b, s.Stats(), err
+}
+
+// CountSolutionsContext counts solutions like CountSolutions, but stops like
+// SolveContext. The count so far is returned with the error.
+func (g *Game) CountSolutionsContext(ctx context.Context, e Enumerator, limit int, budget Budget) (int, SolveStats, error) {
+	s := NewSearch(ctx, budget).bind(g)
+	if err := ctx.Err(); err != nil {
+		return 0, s.Stats(), s.stopped(err)
+	}
+	if c, ok := e.(Counter); ok {
+		n, err := c.Count(s, g, limit)
+		s.finish()
+		return n, s.Stats(), err
+	}
+	var n int
+	err := e.Enumerate(s, g, func(*Board) bool {
+		n++
+		return limit <= 0 || n < limit
+	})
+	s.finish()
+	return n, s.Stats(), err
+}
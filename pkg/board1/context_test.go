@@ -0,0 +1,82 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSolveContextBudget(t *testing.T) {
+	solvers := []Solver{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &PropagationSolver{}}
+	for _, s := range solvers {
+		g := rowAreas(8)
+		_, _, err := g.SolveContext(context.Background(), s, Budget{Nodes: 1})
+		var se *StoppedError
+		if !errors.As(err, &se) || !errors.Is(err, ErrBudget) {
+			t.Errorf("%T: got error %v, want %v", s, err, ErrBudget)
+			continue
+		}
+		if se.Stats.Nodes != 2 {
+			t.Errorf("%T: stopped after %d nodes, want 2", s, se.Stats.Nodes)
+		}
+
+		// Without limits the same game is solved.
+		if _, _, err := g.SolveContext(context.Background(), s, Budget{}); err != nil {
+			t.Errorf("%T: %v", s, err)
+		}
+	}
+}
+
+func TestSolveContextPlacements(t *testing.T) {
+	g := rowAreas(8)
+	_, _, err := g.SolveContext(context.Background(), &AreaSolver{}, Budget{Placements: 10})
+	var se *StoppedError
+	if !errors.As(err, &se) || !errors.Is(err, ErrBudget) {
+		t.Fatalf("got error %v, want %v", err, ErrBudget)
+	}
+	if se.Stats.Placements != 11 {
+		t.Errorf("stopped after %d placements, want 11", se.Stats.Placements)
+	}
+}
+
+func TestSolveContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g := rowAreas(8)
+	if _, _, err := g.SolveContext(ctx, &BitSolver{}, Budget{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCountSolutionsContextDeadline(t *testing.T) {
+	// Counting all solutions of this board takes very long.
+	g := rowAreas(14)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	n, _, err := g.CountSolutionsContext(ctx, &AreaSolver{}, 0, Budget{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v after %d solutions, want %v", err, n, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("stopping took %v", d)
+	}
+	var se *StoppedError
+	if !errors.As(err, &se) || se.Stats.Nodes == 0 {
+		t.Errorf("got error %#v without counts", err)
+	}
+}
+
+func TestRateContext(t *testing.T) {
+	g := loadGame(t, "../../2025-04-24.txt")
+	if _, err := RateContext(context.Background(), g, Budget{Nodes: 5}); !errors.Is(err, ErrBudget) {
+		t.Errorf("got error %v, want %v", err, ErrBudget)
+	}
+	r, err := RateContext(context.Background(), g, Budget{})
+	want := Rate(g)
+	if err != nil || r.Tier != want.Tier || r.Score != want.Score {
+		t.Errorf("got %v, %v, want %v", r, err, want)
+	}
+}
@@ -0,0 +1,243 @@
+package board1
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errContradiction = errors.New("unit has fewer empty fields than queens it needs")
+
+// unit is a row, column or area that must hold exactly stars queens.
+type unit struct {
+	kind  string
+	index int
+	// label is the label of an area.
+	label string
+	cells []Position
+	stars int
+}
+
+// units returns the rows, columns and areas of g, without their holes.
+// Rows and columns that hold at most stars queens are no units, their
+// queens are limited by putQueen.
+func (g *Game) units() []unit {
+	units := make([]unit, 0, g.Rows+g.Cols+len(g.Areas))
+	k := g.stars()
+	hole := g.holeMask()
+	line := func(kind string, index int, cells []Position) {
+		u := unit{kind: kind, index: index, stars: k}
+		for _, p := range cells {
+			if !hole[p[0]*g.Cols+p[1]] {
+				u.cells = append(u.cells, p)
+			}
+		}
+		if len(u.cells) > 0 {
+			units = append(units, u)
+		}
+	}
+	exactRows, exactCols := g.exactLines()
+	if exactRows {
+		for row := range g.Rows {
+			line("row", row, g.row(row))
+		}
+	}
+	if exactCols {
+		for col := range g.Cols {
+			line("column", col, g.column(col))
+		}
+	}
+	for i, a := range g.Areas {
+		units = append(units, unit{kind: "area", index: i, label: a.Label, cells: a.Cells, stars: k})
+	}
+	return units
+}
+
+// scan returns the empty fields of u and the number of queens u still
+// needs. A full unit has no empty fields.
+func (u unit) scan(b *Board) ([]Position, int) {
+	var empty []Position
+	need := max(u.stars, 1)
+	for _, p := range u.cells {
+		switch b.Get(p[0], p[1]) {
+		case Queen:
+			need--
+		case Empty:
+			empty = append(empty, p)
+		}
+	}
+	if need <= 0 {
+		return nil, 0
+	}
+	return empty, need
+}
+
+func (u unit) String() string {
+	if u.label != "" {
+		return fmt.Sprintf("%s %s", u.kind, u.label)
+	}
+	return fmt.Sprintf("%s %d", u.kind, u.index+1)
+}
+
+// row returns the fields of row.
+func (g *Game) row(row int) []Position {
+	cells := make([]Position, g.Cols)
+	for col := range g.Cols {
+		cells[col] = Position{row, col}
+	}
+	return cells
+}
+
+// column returns the fields of col.
+func (g *Game) column(col int) []Position {
+	cells := make([]Position, g.Rows)
+	for row := range g.Rows {
+		cells[row] = Position{row, col}
+	}
+	return cells
+}
+
+// FieldName returns the name of field p as shown to humans, counting from 1,
+// like in the reasons of a Step.
+func FieldName(p Position) string {
+	return fmt.Sprintf("(%d, %d)", p[0]+1, p[1]+1)
+}
+
+// contains reports whether all positions ps are in cells.
+func contains(cells []Position, ps ...Position) bool {
+	for _, p := range ps {
+		if !containsField(cells, p[0], p[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply places the queen of s and blocks its fields.
+// It sets s.Blocked to all fields that got blocked.
+func (g *Game) apply(b *Board, s *Step) error {
+	before := b.Clone()
+	if s.Queen != nil {
+		if err := g.PlaceQueen(b, s.Queen[0], s.Queen[1]); err != nil {
+			return err
+		}
+	}
+	for _, p := range s.Blocked {
+		if b.Get(p[0], p[1]) == Empty {
+			b.Put(p[0], p[1], Blocked)
+		}
+	}
+	s.Blocked = s.Blocked[:0]
+	for i, f := range b.Fields {
+		if f == Blocked && before.Fields[i] == Empty {
+			s.Blocked = append(s.Blocked, Position{i / b.Cols, i % b.Cols})
+		}
+	}
+	return nil
+}
+
+// contradiction returns errContradiction if a unit can no longer get
+// its queens.
+func contradiction(b *Board, units []unit) error {
+	for _, u := range units {
+		if empty, need := u.scan(b); len(empty) < need {
+			return fmt.Errorf("%s: %w", u, errContradiction)
+		}
+	}
+	return nil
+}
+
+// propagate applies rules until none applies anymore.
+// It returns errContradiction if a unit can no longer get its queens.
+func (g *Game) propagate(b *Board, units []unit, rules RuleSet) error {
+	for {
+		if err := contradiction(b, units); err != nil {
+			return err
+		}
+		s, ok := rules.Apply(g, b)
+		if !ok {
+			return nil
+		}
+		if err := g.apply(b, &s); err != nil {
+			return err
+		}
+	}
+}
+
+// PropagationSolver applies deductions until it gets stuck, and only then
+// branches on the open area with the fewest empty fields.
+type PropagationSolver struct {
+	// Rules are the rules to apply, DefaultRules if nil.
+	Rules RuleSet
+}
+
+func (s *PropagationSolver) Solve(sr *Search, g *Game) (*Board, error) {
+	b := g.NewBoard()
+
+	sr.setPhase("search")
+	res, err := s.solveBoard(sr, g, b, g.units(), 0)
+	if err != nil {
+		g.BoardPool.Put(b)
+		return nil, err
+	}
+	return res, nil
+}
+
+// solveBoard solves b, depth is the number of guesses that led to b.
+func (s *PropagationSolver) solveBoard(sr *Search, g *Game, b *Board, units []unit, depth int) (*Board, error) {
+	if err := sr.enter(depth); err != nil {
+		return nil, err
+	}
+	rules := s.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+	if err := g.propagate(b, units, rules); err != nil {
+		if errors.Is(err, errContradiction) {
+			sr.backtrack()
+			return nil, ErrNoSolution
+		}
+		return nil, err
+	}
+
+	// Find the open area with the fewest empty fields, the open row or
+	// column of the shorter dimension for games without areas.
+	kind := "area"
+	if len(g.Areas) == 0 {
+		kind = "row"
+		if exactRows, _ := g.exactLines(); !exactRows {
+			kind = "column"
+		}
+	}
+	var branch []Position
+	for _, u := range units {
+		if u.kind != kind {
+			continue
+		}
+		empty, need := u.scan(b)
+		if need > 0 && (branch == nil || len(empty) < len(branch)) {
+			branch = empty
+		}
+	}
+	if branch == nil {
+		return b, nil
+	}
+
+	for _, p := range branch {
+		sr.branch()
+		nb := g.BoardPool.Get()
+		nb.CopyFrom(b)
+		sr.place()
+		if err := g.PlaceQueen(nb, p[0], p[1]); err != nil {
+			return nil, err
+		}
+		res, err := s.solveBoard(sr, g, nb, units, depth+1)
+		if err == nil {
+			return res, nil
+		}
+		g.BoardPool.Put(nb)
+		if !errors.Is(err, ErrNoSolution) {
+			return nil, err
+		}
+	}
+	return nil, ErrNoSolution
+}
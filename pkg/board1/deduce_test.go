@@ -0,0 +1,115 @@
+package board1
+
+import (
+	"os"
+	"testing"
+)
+
+func loadGame(t testing.TB, name string) *Game {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	a, i, err := LoadAreas(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGame(i, i, a...)
+}
+
+func TestPropagationSolver(t *testing.T) {
+	tests := []struct {
+		name     string
+		branches bool
+	}{
+		{name: "../../2025-4-22.txt"},
+		{name: "../../2025-04-23.txt"},
+		{name: "../../2025-04-24.txt"},
+		{name: "../../cmd/queens/board.txt", branches: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := loadGame(t, tt.name)
+			s := &PropagationSolver{}
+			b, stats, err := g.Solve(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.Print()
+			t.Logf("branches: %d", stats.Branches)
+			if got := stats.Branches > 0; got != tt.branches {
+				t.Errorf("got branches %d, want branches: %v", stats.Branches, tt.branches)
+			}
+
+			// The solution must be one of the solutions.
+			found := false
+			for sol, err := range g.Solutions(&AreaSolver{}) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				found = found || equalFields(sol, b)
+			}
+			if !found {
+				t.Error("board is not a solution")
+			}
+		})
+	}
+}
+
+func equalFields(a, b *Board) bool {
+	for i := range a.Fields {
+		if a.Fields[i] != b.Fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPropagationSolverNoSolution(t *testing.T) {
+	if _, _, err := rowAreas(3).Solve(&PropagationSolver{}); err != ErrNoSolution {
+		t.Errorf("got error %v, want %v", err, ErrNoSolution)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	g := loadGame(t, "../../2025-04-24.txt")
+	steps, err := Explain(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queens := 0
+	for i, s := range steps {
+		t.Logf("step %d: %s", i+1, s.Reason)
+		if s.Queen != nil {
+			queens++
+		}
+	}
+	if queens != g.Rows {
+		t.Errorf("got %d queens placed, want %d", queens, g.Rows)
+	}
+	want, _, err := g.Solve(&AreaSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalFields(steps[len(steps)-1].Board, want) {
+		t.Error("last step is not the solution")
+	}
+}
+
+func TestExplainStuck(t *testing.T) {
+	g := loadGame(t, "../../cmd/queens/board.txt")
+	if _, err := Explain(g); err != ErrStuck {
+		t.Errorf("got error %v, want %v", err, ErrStuck)
+	}
+}
+
+func TestExplainWith(t *testing.T) {
+	// Without rules nothing can be explained.
+	g := loadGame(t, "../../2025-4-22.txt")
+	steps, err := ExplainWith(g, RuleSet{})
+	if err != ErrStuck || len(steps) != 0 {
+		t.Errorf("got %d steps and error %v, want none and %v", len(steps), err, ErrStuck)
+	}
+}
@@ -0,0 +1,368 @@
+package board1
+
+// DLXSolver solves the game as an exact cover problem with
+// Knuth's Algorithm X, using dancing links.
+//
+// Every row, column and area is a primary column that must be covered
+// exactly once, or as many times as the game has stars. Rows or columns
+// that hold at most stars queens, like the columns of a board that is
+// wider than high, are not linked to the root: they work like secondary
+// columns that may be covered stars times. Secondary
+// columns may be covered at most once and keep queens apart by the
+// adjacency rule: with king adjacency every 2x2 window of the board is a
+// secondary column, with the other rules every pair of fields that block
+// each other. With Diagonals every diagonal is a secondary column too.
+// Every field is a row of the matrix that covers its row, column, area and
+// the secondary columns it is part of.
+type DLXSolver struct{}
+
+func (s *DLXSolver) Solve(sr *Search, g *Game) (*Board, error) {
+	var res *Board
+	err := s.search(sr, g, func(cells []Position) (bool, error) {
+		b, err := s.board(g, cells)
+		if err != nil {
+			return false, err
+		}
+		res = b
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrNoSolution
+	}
+	return res, nil
+}
+
+func (s *DLXSolver) Enumerate(sr *Search, g *Game, yield func(*Board) bool) error {
+	return s.search(sr, g, func(cells []Position) (bool, error) {
+		b, err := s.board(g, cells)
+		if err != nil {
+			return false, err
+		}
+		res := b.Clone()
+		g.BoardPool.Put(b)
+		return yield(res), nil
+	})
+}
+
+// board places queens on cells on a board from the pool.
+func (s *DLXSolver) board(g *Game, cells []Position) (*Board, error) {
+	b := g.NewBoard()
+	for _, c := range cells {
+		if err := g.PlaceQueen(b, c[0], c[1]); err != nil {
+			g.BoardPool.Put(b)
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (s *DLXSolver) search(sr *Search, g *Game, found func([]Position) (bool, error)) error {
+	sr.setPhase("prepare")
+	m := newDLX(g)
+	sr.setPhase("search")
+	_, err := m.search(sr, found)
+	return err
+}
+
+type dlxNode struct {
+	left, right, up, down int
+	// col is the header of the column of the node.
+	col int
+	// row is the index of the matrix row, the position of the queen.
+	row int
+}
+
+// dlx is the sparse exact cover matrix. Node 0 is the root, nodes
+// 1 to the number of columns are the column headers.
+type dlx struct {
+	nodes []dlxNode
+	size  []int
+	// need holds the number of times a column must still be covered.
+	need  []int
+	cells []Position
+
+	// solution holds the rows selected so far.
+	solution []int
+}
+
+func newDLX(g *Game) *dlx {
+	rows, cols := g.Rows, g.Cols
+	areas := g.regions()
+	primary := rows + cols + len(areas)
+	around, secondary := secondaryColumns(g)
+	ncols := primary + secondary
+
+	m := &dlx{
+		nodes: make([]dlxNode, ncols+1),
+		size:  make([]int, ncols+1),
+		need:  make([]int, ncols+1),
+	}
+	for c := 1; c <= ncols; c++ {
+		n := &m.nodes[c]
+		// Primary columns are linked to the root below. Secondary
+		// columns are not, they never need to be covered.
+		n.left, n.right = c, c
+		n.up, n.down, n.col = c, c, c
+		m.need[c] = 1
+		if c <= primary {
+			m.need[c] = g.stars()
+		}
+	}
+
+	for a, area := range areas {
+		for _, p := range area.Cells {
+			row, col := p[0], p[1]
+			if row < 0 || row >= rows || col < 0 || col >= cols {
+				continue
+			}
+			columns := []int{
+				1 + row,
+				1 + rows + col,
+				1 + rows + cols + a,
+			}
+			for _, c := range around[row*cols+col] {
+				columns = append(columns, 1+primary+c)
+			}
+			m.addRow(p, columns)
+		}
+	}
+
+	// Link the primary columns to the root, without the rows and columns
+	// that hold at most stars queens or have only holes.
+	exactRows, exactCols := g.exactLines()
+	for c := 1; c <= primary; c++ {
+		switch {
+		case c <= rows+cols && m.size[c] == 0:
+			continue
+		case c <= rows && !exactRows, c > rows && c <= rows+cols && !exactCols:
+			continue
+		}
+		n := &m.nodes[c]
+		n.left, n.right = m.nodes[0].left, 0
+		m.nodes[n.left].right = c
+		m.nodes[0].left = c
+	}
+	return m
+}
+
+// secondaryColumns returns for every field the secondary columns it is
+// part of, and the number of secondary columns.
+func secondaryColumns(g *Game) ([][]int, int) {
+	rows, cols := g.Rows, g.Cols
+	around := make([][]int, rows*cols)
+	if g.Adjacency == KingAdjacency {
+		for row := range rows {
+			for col := range cols {
+				for i := max(row-1, 0); i <= min(row, rows-2); i++ {
+					for j := max(col-1, 0); j <= min(col, cols-2); j++ {
+						around[row*cols+col] = append(around[row*cols+col], i*(cols-1)+j)
+					}
+				}
+			}
+		}
+		return diagonalColumns(g, around, max(rows-1, 0)*max(cols-1, 0))
+	}
+	var n int
+	for row := range rows {
+		for col := range cols {
+			for _, o := range g.Adjacency.Offsets() {
+				// Every pair once, from the field that comes first.
+				i, j := row+o[0], col+o[1]
+				if o[0] < 0 || o[0] == 0 && o[1] < 0 || i >= rows || j < 0 || j >= cols {
+					continue
+				}
+				around[row*cols+col] = append(around[row*cols+col], n)
+				around[i*cols+j] = append(around[i*cols+j], n)
+				n++
+			}
+		}
+	}
+	return diagonalColumns(g, around, n)
+}
+
+// diagonalColumns adds the diagonals as secondary columns after the n
+// columns of around if g has Diagonals.
+func diagonalColumns(g *Game, around [][]int, n int) ([][]int, int) {
+	if !g.Diagonals {
+		return around, n
+	}
+	// Diagonals have a constant row-col, anti-diagonals a constant row+col.
+	diagonals := g.Rows + g.Cols - 1
+	for row := range g.Rows {
+		for col := range g.Cols {
+			f := row*g.Cols + col
+			around[f] = append(around[f], n+row-col+g.Cols-1, n+diagonals+row+col)
+		}
+	}
+	return around, n + 2*diagonals
+}
+
+// addRow appends a matrix row for cell that covers columns.
+func (m *dlx) addRow(cell Position, columns []int) {
+	r := len(m.cells)
+	m.cells = append(m.cells, cell)
+
+	first := len(m.nodes)
+	for i, c := range columns {
+		n := len(m.nodes)
+		m.nodes = append(m.nodes, dlxNode{
+			left:  n - 1,
+			right: n + 1,
+			up:    m.nodes[c].up,
+			down:  c,
+			col:   c,
+			row:   r,
+		})
+		if i == 0 {
+			m.nodes[n].left = first + len(columns) - 1
+		}
+		if i == len(columns)-1 {
+			m.nodes[n].right = first
+		}
+		m.nodes[m.nodes[c].up].down = n
+		m.nodes[c].up = n
+		m.size[c]++
+	}
+}
+
+func (m *dlx) cover(c int) {
+	nodes := m.nodes
+	nodes[nodes[c].right].left = nodes[c].left
+	nodes[nodes[c].left].right = nodes[c].right
+	for i := nodes[c].down; i != c; i = nodes[i].down {
+		for j := nodes[i].right; j != i; j = nodes[j].right {
+			nodes[nodes[j].down].up = nodes[j].up
+			nodes[nodes[j].up].down = nodes[j].down
+			m.size[nodes[j].col]--
+		}
+	}
+}
+
+func (m *dlx) uncover(c int) {
+	nodes := m.nodes
+	for i := nodes[c].up; i != c; i = nodes[i].up {
+		for j := nodes[i].left; j != i; j = nodes[j].left {
+			m.size[nodes[j].col]++
+			nodes[nodes[j].down].up = j
+			nodes[nodes[j].up].down = j
+		}
+	}
+	nodes[nodes[c].right].left = c
+	nodes[nodes[c].left].right = c
+}
+
+// search returns false when found asked to stop.
+func (m *dlx) search(sr *Search, found func([]Position) (bool, error)) (bool, error) {
+	nodes := m.nodes
+	if nodes[0].right == 0 {
+		cells := make([]Position, len(m.solution))
+		for i, r := range m.solution {
+			cells[i] = m.cells[r]
+		}
+		return found(cells)
+	}
+	if err := sr.enter(len(m.solution)); err != nil {
+		return false, err
+	}
+
+	// Choose the column with the fewest rows to spare.
+	c := nodes[0].right
+	for j := nodes[c].right; j != 0; j = nodes[j].right {
+		if m.size[j]-m.need[j] < m.size[c]-m.need[c] {
+			c = j
+		}
+	}
+	if m.size[c] < m.need[c] {
+		sr.backtrack()
+		return true, nil
+	}
+
+	// Branch on the first row selected in c. The rows tried before are
+	// hidden in the next branches, so every set of rows is found once.
+	var tried []int
+	defer func() {
+		for i := len(tried) - 1; i >= 0; i-- {
+			m.unhide(tried[i])
+		}
+	}()
+	for r := nodes[c].down; r != c && m.size[c] >= m.need[c]; r = nodes[r].down {
+		sr.place()
+		m.solution = append(m.solution, nodes[r].row)
+		covered := m.selectRow(r)
+		more, err := m.search(sr, found)
+		m.unselectRow(r, covered)
+		m.solution = m.solution[:len(m.solution)-1]
+		if err != nil || !more {
+			return false, err
+		}
+		m.hide(r)
+		tried = append(tried, r)
+	}
+	return true, nil
+}
+
+// selectRow takes the row of node r in the solution. It hides the row and
+// covers the columns that need no more rows, and returns these columns.
+func (m *dlx) selectRow(r int) []int {
+	m.hide(r)
+	var covered []int
+	j := r
+	for {
+		c := m.nodes[j].col
+		m.need[c]--
+		if m.need[c] == 0 {
+			m.cover(c)
+			covered = append(covered, c)
+		}
+		if j = m.nodes[j].right; j == r {
+			return covered
+		}
+	}
+}
+
+// unselectRow undoes selectRow.
+func (m *dlx) unselectRow(r int, covered []int) {
+	for i := len(covered) - 1; i >= 0; i-- {
+		m.uncover(covered[i])
+	}
+	j := r
+	for {
+		m.need[m.nodes[j].col]++
+		if j = m.nodes[j].right; j == r {
+			break
+		}
+	}
+	m.unhide(r)
+}
+
+// hide removes the row of node r from its columns.
+func (m *dlx) hide(r int) {
+	nodes := m.nodes
+	j := r
+	for {
+		nodes[nodes[j].down].up = nodes[j].up
+		nodes[nodes[j].up].down = nodes[j].down
+		m.size[nodes[j].col]--
+		if j = nodes[j].right; j == r {
+			return
+		}
+	}
+}
+
+// unhide puts the row of node r back in its columns.
+func (m *dlx) unhide(r int) {
+	nodes := m.nodes
+	j := r
+	for {
+		j = nodes[j].left
+		m.size[nodes[j].col]++
+		nodes[nodes[j].down].up = j
+		nodes[nodes[j].up].down = j
+		if j == r {
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package board1
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDLXSolverCount(t *testing.T) {
+	for n := 4; n <= 7; n++ {
+		want, err := rowAreas(n).CountSolutions(&AreaSolver{}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := rowAreas(n).CountSolutions(&DLXSolver{}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("size %d: got %d solutions, want %d", n, got, want)
+		}
+	}
+}
+
+func TestDLXSolver(t *testing.T) {
+	for _, name := range []string{"../../2025-4-22.txt", "../../2025-04-23.txt", "../../2025-04-24.txt"} {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			a, i, err := LoadAreas(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g := NewGame(i, i, a...)
+			want, _, err := g.Solve(&AreaSolver{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, stats, err := g.Solve(&DLXSolver{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range want.Fields {
+				if got.Fields[i] != want.Fields[i] {
+					got.Print()
+					t.Fatal("solutions differ")
+				}
+			}
+			t.Logf("stats:\n%v", stats)
+		})
+	}
+}
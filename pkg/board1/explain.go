@@ -0,0 +1,75 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrStuck = errors.New("no rule applies")
+
+// Step is one step in the explanation of a solution.
+type Step struct {
+	// Rule is the name of the rule used.
+	Rule string
+	// Difficulty is the difficulty of the rule used.
+	Difficulty int
+	// Reason explains the step, like "area 3 confined to row 5".
+	Reason string
+	// Queen is the field a queen is placed on, nil if none.
+	Queen Position
+	// Blocked holds the fields this step blocks.
+	Blocked []Position
+	// Board is a snapshot of the board after the step.
+	Board *Board
+}
+
+// Explain solves g step by step with DefaultRules.
+func Explain(g *Game) ([]Step, error) {
+	return ExplainWith(g, DefaultRules)
+}
+
+// ExplainWith solves g step by step with rules, without guessing.
+// If the rules get stuck it returns the steps so far and ErrStuck.
+func ExplainWith(g *Game, rules RuleSet) ([]Step, error) {
+	return explain(NewSearch(context.Background(), Budget{}), g, rules)
+}
+
+// explain is ExplainWith, but stops with the error of sr.ctx.
+func explain(sr *Search, g *Game, rules RuleSet) ([]Step, error) {
+	b := g.NewBoard()
+	defer g.BoardPool.Put(b)
+	units := g.units()
+
+	var steps []Step
+	for {
+		if err := sr.ctx.Err(); err != nil {
+			return steps, sr.stopped(err)
+		}
+		if err := contradiction(b, units); err != nil {
+			return steps, fmt.Errorf("%w: %w", ErrNoSolution, err)
+		}
+		if solved(b, units) {
+			return steps, nil
+		}
+		s, ok := rules.Apply(g, b)
+		if !ok {
+			return steps, ErrStuck
+		}
+		if err := g.apply(b, &s); err != nil {
+			return steps, err
+		}
+		s.Board = b.Clone()
+		steps = append(steps, s)
+	}
+}
+
+// solved reports whether every unit has its queens.
+func solved(b *Board, units []unit) bool {
+	for _, u := range units {
+		if _, need := u.scan(b); need > 0 {
+			return false
+		}
+	}
+	return true
+}
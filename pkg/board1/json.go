@@ -0,0 +1,230 @@
+package board1
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PuzzleSchema is the JSON schema of the puzzle format.
+//
+//go:embed puzzle.schema.json
+var PuzzleSchema []byte
+
+// Puzzle is a puzzle with an optional start position, solution and
+// metadata, as stored in JSON. Positions are 0-based row, col pairs.
+type Puzzle struct {
+	Size  Size   `json:"size"`
+	Areas []Area `json:"areas"`
+	// Holes are the fields that are not part of the board.
+	Holes []Position `json:"holes,omitempty"`
+	// Stars is the number of queens per row, column and area, 1 if 0.
+	Stars int `json:"stars,omitempty"`
+	// Adjacency is the rule for the fields a queen blocks around it,
+	// king if not set.
+	Adjacency Adjacency `json:"adjacency,omitempty"`
+	// Diagonals makes queens attack along their full diagonals.
+	Diagonals bool `json:"diagonals,omitempty"`
+
+	// Queens and Blocked are the fields of the start position.
+	Queens  []Position `json:"queens,omitempty"`
+	Blocked []Position `json:"blocked,omitempty"`
+
+	// Solution holds the queens of the solution.
+	Solution []Position `json:"solution,omitempty"`
+
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// Size is the number of rows and columns of a board.
+type Size struct {
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+}
+
+// Meta describes where a puzzle comes from.
+type Meta struct {
+	Source string `json:"source,omitempty"`
+	// Date is the date the puzzle was published, like "2025-04-24".
+	Date       string `json:"date,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// NewPuzzle returns the puzzle of g, without start position and solution.
+func NewPuzzle(g *Game) *Puzzle {
+	return &Puzzle{
+		Size:      Size{Rows: g.Rows, Cols: g.Cols},
+		Areas:     g.Areas,
+		Holes:     g.Holes,
+		Stars:     g.Stars,
+		Adjacency: g.Adjacency,
+		Diagonals: g.Diagonals,
+	}
+}
+
+// Game returns a new game for the puzzle.
+func (p *Puzzle) Game() *Game {
+	g := NewGame(p.Size.Rows, p.Size.Cols, p.Areas...)
+	g.Stars = p.Stars
+	g.Adjacency = p.Adjacency
+	g.Diagonals = p.Diagonals
+	g.Holes = p.Holes
+	return g
+}
+
+// Position returns the start position of the puzzle on a new board.
+// The fields attacked by the queens are blocked.
+func (p *Puzzle) Position(g *Game) (*Board, error) {
+	b := &Board{
+		Fields: make([]State, g.Rows*g.Cols),
+		Rows:   g.Rows,
+		Cols:   g.Cols,
+	}
+	g.putHoles(b)
+	for _, q := range p.Queens {
+		if err := g.PlaceQueen(b, q[0], q[1]); err != nil {
+			return nil, fmt.Errorf("queen %s: %w", FieldName(q), err)
+		}
+	}
+	for _, f := range p.Blocked {
+		switch b.Get(f[0], f[1]) {
+		case Queen:
+			return nil, fmt.Errorf("blocked field %s holds a queen", FieldName(f))
+		case Hole:
+			return nil, fmt.Errorf("blocked field %s is a hole", FieldName(f))
+		}
+		b.Put(f[0], f[1], Blocked)
+	}
+	return b, nil
+}
+
+// SetSolution stores the queens of b as the solution.
+func (p *Puzzle) SetSolution(b *Board) {
+	p.Solution = nil
+	for row := range b.Rows {
+		for col := range b.Cols {
+			if b.Get(row, col) == Queen {
+				p.Solution = append(p.Solution, Position{row, col})
+			}
+		}
+	}
+}
+
+// validate checks the areas like Validate, that every position is a
+// field on the board, and that the queens of the start position and of
+// the solution do not block each other.
+func (p *Puzzle) validate() error {
+	var is []Issue
+	pairs := func(name string, ps []Position) {
+		for _, f := range ps {
+			if len(f) != 2 {
+				is = append(is, Issue{Msg: fmt.Sprintf("%s %v is not a row, column pair", name, []int(f))})
+			}
+		}
+	}
+	for a, area := range p.Areas {
+		pairs("field of "+areaName(p.Areas, a), area.Cells)
+	}
+	pairs("hole", p.Holes)
+	pairs("queen", p.Queens)
+	pairs("blocked field", p.Blocked)
+	pairs("solution queen", p.Solution)
+	if p.Stars < 0 {
+		is = append(is, Issue{Msg: fmt.Sprintf("stars %d is negative", p.Stars)})
+	}
+	if p.Adjacency.String() == "?" {
+		is = append(is, Issue{Msg: fmt.Sprintf("unknown adjacency %d", int(p.Adjacency))})
+	}
+	if len(is) > 0 {
+		return issues(is)
+	}
+
+	is = validateAreas(p.Size.Rows, p.Size.Cols, p.Areas, p.Holes, func(p Position) (int, int) {
+		return p[0] + 1, p[1] + 1
+	})
+	onBoard := func(name string, ps []Position) {
+		for _, f := range ps {
+			if f[0] < 0 || f[0] >= p.Size.Rows || f[1] < 0 || f[1] >= p.Size.Cols {
+				is = append(is, Issue{Msg: fmt.Sprintf("%s %s is outside the board", name, FieldName(f))})
+			}
+		}
+	}
+	onBoard("queen", p.Queens)
+	onBoard("blocked field", p.Blocked)
+	onBoard("solution queen", p.Solution)
+	if len(is) > 0 {
+		return issues(is)
+	}
+
+	g := p.Game()
+	apart := func(name string, ps []Position) {
+		b := &Board{Fields: make([]State, g.Rows*g.Cols), Rows: g.Rows, Cols: g.Cols}
+		g.putHoles(b)
+		for _, f := range ps {
+			switch b.Get(f[0], f[1]) {
+			case Empty:
+			case Hole:
+				is = append(is, Issue{Msg: fmt.Sprintf("%s %s is on a hole", name, FieldName(f))})
+				continue
+			default:
+				is = append(is, Issue{Msg: fmt.Sprintf("%s %s is blocked by another queen", name, FieldName(f))})
+				continue
+			}
+			g.putQueen(b, f[0], f[1])
+		}
+	}
+	apart("queen", p.Queens)
+	apart("solution queen", p.Solution)
+	return issues(is)
+}
+
+// DecodePuzzle reads a puzzle in JSON and validates it.
+// Issues are returned as a *ValidationError.
+func DecodePuzzle(r io.Reader) (*Puzzle, error) {
+	var p Puzzle
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, fmt.Errorf("decode puzzle: %w", err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// EncodePuzzle writes p as indented JSON.
+func EncodePuzzle(w io.Writer, p *Puzzle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// LoadPuzzle reads a puzzle in JSON or in the format read by LoadGame.
+// The format is JSON if the first character that is not a space is '{'.
+func LoadPuzzle(r io.Reader) (*Puzzle, error) {
+	br := bufio.NewReader(r)
+	// Peek, the text format needs all lines to report issues.
+	for n := 1; ; n++ {
+		buf, err := br.Peek(n)
+		if len(buf) < n {
+			if err != io.EOF && err != bufio.ErrBufferFull {
+				return nil, err
+			}
+			break
+		}
+		c := buf[n-1]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+		if c == '{' {
+			return DecodePuzzle(br)
+		}
+		break
+	}
+	g, err := LoadGame(br)
+	if err != nil {
+		return nil, err
+	}
+	return NewPuzzle(g), nil
+}
@@ -0,0 +1,182 @@
+package board1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPuzzleRoundTrip(t *testing.T) {
+	g := loadGame(t, "../../2025-04-24.txt")
+	g.Areas[0].Color = "#ffc992"
+	p := NewPuzzle(g)
+	p.Queens = []Position{{0, 0}}
+	p.Blocked = []Position{{2, 2}}
+	p.Meta = &Meta{Source: "linkedin", Date: "2025-04-24", Difficulty: "expert"}
+	b, _, err := g.Solve(&BitSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetSolution(b)
+
+	var buf bytes.Buffer
+	if err := EncodePuzzle(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadPuzzle(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Size != p.Size || *got.Meta != *p.Meta {
+		t.Errorf("got size %v meta %v, want %v %v", got.Size, got.Meta, p.Size, p.Meta)
+	}
+	if len(got.Areas) != len(p.Areas) || got.Areas[0].Color != "#ffc992" || got.Areas[1].Label != p.Areas[1].Label {
+		t.Errorf("got areas %v, want %v", got.Areas, p.Areas)
+	}
+	if len(got.Solution) != g.Rows {
+		t.Errorf("got %d solution queens, want %d", len(got.Solution), g.Rows)
+	}
+
+	pos, err := got.Position(got.Game())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos.Get(0, 0) != Queen || pos.Get(0, 1) != Blocked || pos.Get(2, 2) != Blocked {
+		t.Errorf("wrong start position")
+		pos.Print()
+	}
+}
+
+func TestLoadPuzzleText(t *testing.T) {
+	f, err := os.Open("../../2025-04-23.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	p, err := LoadPuzzle(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Size.Rows != 9 || len(p.Areas) != 9 {
+		t.Errorf("got size %v with %d areas", p.Size, len(p.Areas))
+	}
+
+	// Issues keep the lines of the file, leading empty lines included.
+	_, err = LoadPuzzle(strings.NewReader("\n0 0\n1\n"))
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("got error %v, want a validation error", err)
+	}
+	if ve.Issues[0].Line != 3 {
+		t.Errorf("got issue %v", ve.Issues[0])
+	}
+}
+
+func TestDecodePuzzleInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		msgs  []string
+	}{
+		{
+			name:  "queen outside",
+			input: `{"size":{"rows":2,"cols":2},"areas":[{"label":"a","cells":[[0,0],[0,1]]},{"label":"b","cells":[[1,0],[1,1]]}],"queens":[[2,0]]}`,
+			msgs:  []string{"queen (3, 1) is outside the board"},
+		},
+		{
+			name:  "no pair",
+			input: `{"size":{"rows":1,"cols":1},"areas":[{"label":"a","cells":[[0]]}]}`,
+			msgs:  []string{"field of area a [0] is not a row, column pair"},
+		},
+		{
+			name:  "negative size",
+			input: `{"size":{"rows":-1,"cols":2},"areas":[]}`,
+			msgs:  []string{"board of -1x2 has a negative size"},
+		},
+		{
+			name:  "huge size",
+			input: `{"size":{"rows":3000000000,"cols":3000000000},"areas":[]}`,
+			msgs:  []string{"board of 3000000000x3000000000 is larger than 1000x1000"},
+		},
+		{
+			name:  "no size",
+			input: `{"areas":[]}`,
+			msgs:  []string{"board has no fields"},
+		},
+		{
+			name:  "negative stars",
+			input: `{"size":{"rows":1,"cols":1},"areas":[{"label":"a","cells":[[0,0]]}],"stars":-1}`,
+			msgs:  []string{"stars -1 is negative"},
+		},
+		{
+			name:  "field in no area",
+			input: `{"size":{"rows":1,"cols":2},"areas":[{"label":"a","cells":[[0,0]]}]}`,
+			msgs:  []string{"line 1, column 2: field is in no area"},
+		},
+		{
+			name:  "hole in area",
+			input: `{"size":{"rows":1,"cols":2},"areas":[{"label":"a","cells":[[0,0],[0,1]]}],"holes":[[0,1],[1,1]]}`,
+			msgs: []string{
+				"hole (2, 2) is outside the board",
+				"line 1, column 2: field is a hole and in area a",
+			},
+		},
+		{
+			name:  "queen on hole",
+			input: `{"size":{"rows":1,"cols":2},"areas":[{"label":"a","cells":[[0,0]]}],"holes":[[0,1]],"queens":[[0,1]]}`,
+			msgs:  []string{"queen (1, 2) is on a hole"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadPuzzle(strings.NewReader(tt.input))
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("got error %v, want a validation error", err)
+			}
+			var msgs []string
+			for _, is := range ve.Issues {
+				msgs = append(msgs, is.Error())
+			}
+			if !slices.Equal(msgs, tt.msgs) {
+				t.Errorf("got %q, want %q", msgs, tt.msgs)
+			}
+		})
+	}
+}
+
+func TestPuzzleSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(PuzzleSchema, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema["title"] != "Queens puzzle" {
+		t.Errorf("got title %v", schema["title"])
+	}
+
+	// Every tier is a valid difficulty.
+	var difficulty struct {
+		Properties struct {
+			Meta struct {
+				Properties struct {
+					Difficulty struct {
+						Enum []string `json:"enum"`
+					} `json:"difficulty"`
+				} `json:"properties"`
+			} `json:"meta"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(PuzzleSchema, &difficulty); err != nil {
+		t.Fatal(err)
+	}
+	enum := difficulty.Properties.Meta.Properties.Difficulty.Enum
+	for tier := Easy; tier <= Guessing; tier++ {
+		if !slices.Contains(enum, tier.String()) {
+			t.Errorf("tier %s is not in the difficulty enum %q", tier, enum)
+		}
+	}
+}
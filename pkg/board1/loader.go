@@ -2,36 +2,155 @@ package board1
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"strings"
 )
 
+// holeSymbol marks a field that is not part of the board.
+const holeSymbol = '.'
+
+// areaSymbols are the symbols WriteAreas uses for areas without
+// a usable label, in order.
+const areaSymbols = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// DefaultLabel returns the label WriteAreas uses for area i
+// if the areas have no usable labels.
+func DefaultLabel(i int) string {
+	if i < 0 || i >= len(areaSymbols) {
+		return ""
+	}
+	return areaSymbols[i : i+1]
+}
+
+// areaLabels returns the labels of areas as one string, if all labels are
+// different symbols of one character. It returns "" otherwise.
+func areaLabels(areas []Area) string {
+	seen := map[rune]bool{}
+	var labels []rune
+	for _, a := range areas {
+		l := []rune(a.Label)
+		if len(l) != 1 || l[0] == ' ' || l[0] == '\t' || l[0] == holeSymbol || seen[l[0]] {
+			return ""
+		}
+		seen[l[0]] = true
+		labels = append(labels, l[0])
+	}
+	return string(labels)
+}
+
+// LoadAreas reads a square puzzle without holes like LoadGame, and returns
+// its areas and number of rows.
 func LoadAreas(r io.Reader) ([]Area, int, error) {
-	m := map[rune]Area{}
+	g, err := LoadGame(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if g.Rows != g.Cols || len(g.Holes) > 0 {
+		return nil, 0, fmt.Errorf("board of %dx%d is not square or has holes, load it with LoadGame", g.Rows, g.Cols)
+	}
+	return g.Areas, g.Rows, nil
+}
+
+// LoadGame reads a puzzle, one row per line with a symbol per field.
+// Fields with the same symbol form an area, a . is a hole that is not part
+// of the board. Spaces, tabs and empty lines are ignored. The symbol is the
+// label of the area, the areas are in the order their symbols first appear.
+// All rows must have the same number of fields, holes included.
+// The puzzle is validated, issues are returned as a *ValidationError with
+// the lines and columns in r.
+func LoadGame(r io.Reader) (*Game, error) {
+	var res []Area
+	var holes []Position
+	// index holds the index in res of every symbol
+	index := map[rune]int{}
+	// at holds the line and column of every field.
+	at := map[[2]int][2]int{}
+	var is []Issue
 
 	s := bufio.NewScanner(r)
-	var x int
+	var x, cols, lineNo int
 	for s.Scan() {
+		lineNo++
 		line := s.Text()
 		// skip empty line
-		if 	line == "" {
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		// remove all spaces
-		line = strings.ReplaceAll(line, " ", "")
-		line = strings.ReplaceAll(line, "\t", "")
 		var y int
-		for _, c := range line {
-			m[c] = append(m[c], Position{x, y})
+		for col, c := range []rune(line) {
+			// skip all spaces
+			if c == ' ' || c == '\t' {
+				continue
+			}
+			at[[2]int{x, y}] = [2]int{lineNo, col + 1}
+			if c == holeSymbol {
+				holes = append(holes, Position{x, y})
+				y++
+				continue
+			}
+			i, ok := index[c]
+			if !ok {
+				i = len(res)
+				index[c] = i
+				res = append(res, Area{Label: string(c)})
+			}
+			res[i].Cells = append(res[i].Cells, Position{x, y})
 			y++
 		}
+		if x == 0 {
+			cols = y
+		} else if y != cols {
+			is = append(is, Issue{Line: lineNo, Col: len([]rune(line)), Msg: fmt.Sprintf("row has %d fields, the first row has %d", y, cols)})
+		}
 		x++
 	}
-	res := make([]Area, 0, len(m))
-	for _, a := range m {
-		res = append(res, a)
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(is) == 0 {
+		is = validateAreas(x, cols, res, holes, func(p Position) (int, int) {
+			lc := at[[2]int{p[0], p[1]}]
+			return lc[0], lc[1]
+		})
 	}
-	return res, x, nil
+	if err := issues(is); err != nil {
+		return nil, err
+	}
+	g := NewGame(x, cols, res...)
+	g.Holes = holes
+	return g, nil
 }
 
+// WriteAreas writes areas as tab separated symbols, in the format read by
+// LoadGame. Fields in no area are written as holes.
+func WriteAreas(w io.Writer, rows, cols int, areas []Area) error {
+	symbols := areaLabels(areas)
+	if symbols == "" {
+		if len(areas) > len(areaSymbols) {
+			return fmt.Errorf("cannot write more than %d areas", len(areaSymbols))
+		}
+		symbols = areaSymbols
+	}
+	grid := make([]rune, rows*cols)
+	for i := range grid {
+		grid[i] = holeSymbol
+	}
+	for i, a := range areas {
+		for _, p := range a.Cells {
+			grid[p[0]*cols+p[1]] = []rune(symbols)[i]
+		}
+	}
 
+	bw := bufio.NewWriter(w)
+	for i := range rows {
+		for j := range cols {
+			if j > 0 {
+				bw.WriteByte('\t')
+			}
+			bw.WriteRune(grid[i*cols+j])
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
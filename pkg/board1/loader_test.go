@@ -26,7 +26,7 @@ func TestLoad(t *testing.T) {
 
 	g := NewGame(i, i, a...)
 	s := &SimpleSolver{}
-	b, err := g.Solve(s)
+	b, _, err := g.Solve(s)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -54,15 +54,14 @@ func TestLoad2(t *testing.T) {
 
 	g := NewGame(i, i, a...)
 	s := &SimpleSolver{}
-	b, err := g.Solve(s)
+	b, stats, err := g.Solve(s)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	b.Print()
 
-	t.Logf("solve called: %d", g.SolveCalled())
-	t.Logf("boards used: %d", g.BoardPool.MaxEntries())
+	t.Logf("stats:\n%v", stats)
 }
 
 
@@ -86,15 +85,14 @@ func TestLoad3(t *testing.T) {
 
 	g := NewGame(i, i, a...)
 	s := &SimpleSolver{}
-	b, err := g.Solve(s)
+	b, stats, err := g.Solve(s)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	b.Print()
 
-	t.Logf("solve called: %d", g.SolveCalled())
-	t.Logf("boards used: %d", g.BoardPool.MaxEntries())
+	t.Logf("stats:\n%v", stats)
 }
 
 func TestLoad4(t *testing.T) {
@@ -117,15 +115,14 @@ func TestLoad4(t *testing.T) {
 
 	g := NewGame(i, i, a...)
 	s := &AreaSolver{}
-	b, err := g.Solve(s)
+	b, stats, err := g.Solve(s)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	b.Print()
 
-	t.Logf("solve called: %d", g.SolveCalled())
-	t.Logf("boards used: %d", g.BoardPool.MaxEntries())
+	t.Logf("stats:\n%v", stats)
 }
 
 func TestLoad5(t *testing.T) {
@@ -146,13 +143,71 @@ func TestLoad5(t *testing.T) {
 
 	g := NewGame(i, i, a...)
 	s := &AreaSolver{}
-	b, err := g.Solve(s)
+	b, stats, err := g.Solve(s)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	b.Print()
 
-	t.Logf("solve called: %d", g.SolveCalled())
-	t.Logf("boards used: %d", g.BoardPool.MaxEntries())
+	t.Logf("stats:\n%v", stats)
+}
+
+func TestLoadLabels(t *testing.T) {
+	const board = `b	b	a
+c	a	a
+c	c	a
+`
+	a, i, err := LoadAreas(strings.NewReader(board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, area := range a {
+		labels = append(labels, area.Label)
+	}
+	if got := strings.Join(labels, ""); got != "bac" {
+		t.Errorf("got labels in order %q, want %q", got, "bac")
+	}
+
+	// Writing keeps the labels.
+	var sb strings.Builder
+	if err := WriteAreas(&sb, i, i, a); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != board {
+		t.Errorf("got\n%s\nwant\n%s", sb.String(), board)
+	}
+
+	// Solving does not change the order.
+	g := NewGame(i, i, a...)
+	if _, _, err := g.Solve(&AreaSolver{}); err != nil && err != ErrNoSolution {
+		t.Fatal(err)
+	}
+	if area, ok := g.Area("b"); !ok || g.Areas[0].Label != "b" || len(area.Cells) != 2 {
+		t.Error("areas changed by solving")
+	}
+}
+
+func TestExplainStable(t *testing.T) {
+	var want []string
+	for range 5 {
+		g := loadGame(t, "../../2025-04-24.txt")
+		steps, err := Explain(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, s := range steps {
+			got = append(got, s.Reason)
+		}
+		if want == nil {
+			want = got
+			continue
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Fatalf("got steps\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+	t.Log(strings.Join(want, "\n"))
 }
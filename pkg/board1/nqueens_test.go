@@ -0,0 +1,161 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// nQueensCounts holds the number of solutions of the N-queens puzzle for
+// N from 1 on, see OEIS A000170.
+var nQueensCounts = []int{1, 0, 0, 2, 10, 4, 40, 92, 352, 724, 2680, 14200, 73712, 365596, 2279184, 14772512}
+
+func TestNQueens(t *testing.T) {
+	tests := []struct {
+		e   Enumerator
+		max int
+	}{
+		{e: &SimpleSolver{}, max: 7},
+		{e: &AreaSolver{}, max: 9},
+		{e: &DLXSolver{}, max: 9},
+		{e: &BitSolver{}, max: 11},
+		{e: &ParallelSolver{}, max: 11},
+	}
+	for _, tt := range tests {
+		for n := 1; n <= tt.max; n++ {
+			got, err := NQueens(n).CountSolutions(tt.e, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := nQueensCounts[n-1]; got != want {
+				t.Errorf("%T: size %d: got %d solutions, want %d", tt.e, n, got, want)
+			}
+		}
+	}
+}
+
+func TestCounterLimit(t *testing.T) {
+	for _, e := range []Enumerator{&BitSolver{}, &ParallelSolver{}} {
+		g := NQueens(10)
+		if n, err := g.CountSolutions(e, 100); n != 100 || err != nil {
+			t.Errorf("%T: got %d solutions, %v, want 100", e, n, err)
+		}
+		_, _, err := g.CountSolutionsContext(context.Background(), e, 0, Budget{Nodes: 50})
+		if !errors.Is(err, ErrBudget) {
+			t.Errorf("%T: got error %v, want %v", e, err, ErrBudget)
+		}
+	}
+}
+
+// checkNQueens fails if two queens of b share a diagonal.
+func checkNQueens(t *testing.T, b *Board) {
+	t.Helper()
+	var queens []Position
+	for i, s := range b.Fields {
+		if s == Queen {
+			queens = append(queens, Position{i / b.Cols, i % b.Cols})
+		}
+	}
+	for i, q := range queens {
+		for _, o := range queens[i+1:] {
+			if abs(q[0]-o[0]) == abs(q[1]-o[1]) {
+				t.Errorf("queens on (%d, %d) and (%d, %d) share a diagonal", q[0]+1, q[1]+1, o[0]+1, o[1]+1)
+			}
+		}
+	}
+}
+
+func TestNQueensSolve(t *testing.T) {
+	for _, s := range []Solver{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &PropagationSolver{}, &ParallelSolver{}} {
+		g := NQueens(8)
+		b, _, err := g.Solve(s)
+		if err != nil {
+			t.Fatalf("%T: %v", s, err)
+		}
+		checkStars(t, g, b)
+		checkNQueens(t, b)
+	}
+	if _, _, err := NQueens(3).Solve(&BitSolver{}); err != ErrNoSolution {
+		t.Errorf("got error %v, want %v", err, ErrNoSolution)
+	}
+}
+
+func TestDiagonalsWithAreas(t *testing.T) {
+	const board = `0	0	1	1	1	2	2	2
+0	0	1	1	1	2	2	2
+0	0	4	1	1	2	2	2
+0	0	4	1	3	3	2	2
+4	4	4	4	3	3	5	5
+4	4	4	7	5	5	5	5
+6	6	7	7	7	7	7	5
+6	7	7	7	7	7	7	7
+`
+	a, i, err := LoadAreas(strings.NewReader(board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []Enumerator{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &ParallelSolver{}} {
+		g := NewGame(i, i, a...)
+		g.Diagonals = true
+		var got int
+		for b, err := range g.Solutions(e) {
+			if err != nil {
+				t.Fatalf("%T: %v", e, err)
+			}
+			checkStars(t, g, b)
+			checkNQueens(t, b)
+			got++
+		}
+		// The areas leave 233 solutions without diagonals.
+		if got != 5 {
+			t.Errorf("%T: got %d solutions, want 5", e, got)
+		}
+	}
+}
+
+func TestPuzzleDiagonals(t *testing.T) {
+	var sb strings.Builder
+	if err := EncodePuzzle(&sb, NewPuzzle(NQueens(4))); err != nil {
+		t.Fatal(err)
+	}
+	p, err := DecodePuzzle(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := p.Game()
+	if !g.Diagonals || len(g.Areas) != 0 {
+		t.Errorf("got diagonals %v and %d areas", g.Diagonals, len(g.Areas))
+	}
+	if n, err := g.CountSolutions(&BitSolver{}, 0); n != 2 || err != nil {
+		t.Errorf("got %d solutions, %v, want 2", n, err)
+	}
+}
+
+// BenchmarkNQueens counts the solutions of the N-queens puzzle. The larger
+// sizes take minutes, run them with -bench NQueens -benchtime 1x.
+func BenchmarkNQueens(b *testing.B) {
+	enumerators := []struct {
+		name string
+		e    Enumerator
+	}{
+		{name: "bit", e: &BitSolver{}},
+		{name: "parallel", e: &ParallelSolver{}},
+	}
+	for _, en := range enumerators {
+		for n := 12; n <= len(nQueensCounts); n++ {
+			b.Run(fmt.Sprintf("%s/%d", en.name, n), func(b *testing.B) {
+				for range b.N {
+					got, err := NQueens(n).CountSolutions(en.e, 0)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if want := nQueensCounts[n-1]; got != want {
+						b.Fatalf("got %d solutions, want %d", got, want)
+					}
+				}
+			})
+		}
+	}
+}
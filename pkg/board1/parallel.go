@@ -0,0 +1,208 @@
+package board1
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelSolver splits the search of the AreaSolver on the fields of the
+// smallest area, and searches these branches with a pool of goroutines.
+// Solve stops all workers at the first solution. Enumerate yields the
+// solutions in the order they are found, which differs between runs.
+type ParallelSolver struct {
+	// Workers is the number of goroutines, GOMAXPROCS if 0.
+	Workers int
+}
+
+func (s *ParallelSolver) Solve(sr *Search, g *Game) (*Board, error) {
+	var res *Board
+	err := s.search(sr, g, func(b *Board) bool {
+		res = b
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrNoSolution
+	}
+	return res, nil
+}
+
+func (s *ParallelSolver) Enumerate(sr *Search, g *Game, yield func(*Board) bool) error {
+	return s.search(sr, g, yield)
+}
+
+// search calls found for every solution, in the calling goroutine, until
+// found returns false.
+func (s *ParallelSolver) search(sr *Search, g *Game, found func(*Board) bool) error {
+	sr.setPhase("sort")
+	areas := g.sortedAreas()
+	sr.setPhase("search")
+	if len(areas) == 0 {
+		b := g.NewBoard()
+		defer g.BoardPool.Put(b)
+		found(b.Clone())
+		return nil
+	}
+	if err := sr.enter(0); err != nil {
+		return err
+	}
+
+	// The workers stop when ctx is done, they share the counts of sr.
+	ctx, cancel := context.WithCancel(sr.ctx)
+	defer cancel()
+	sub := sr.withContext(ctx)
+
+	solutions := make(chan *Board)
+	var firstErr error
+	go func() {
+		firstErr = s.run(ctx, cancel, areas[len(areas)-1].Cells, func(p Position) error {
+			return s.branch(sub, g, areas, p, solutions)
+		})
+		close(solutions)
+	}()
+
+	stopped := false
+	for b := range solutions {
+		if !stopped && !found(b) {
+			// The workers stop with errors of ctx, they do not count.
+			stopped = true
+			cancel()
+		}
+	}
+	switch {
+	case stopped:
+		return nil
+	case firstErr != nil:
+		return firstErr
+	case ctx.Err() != nil:
+		// The caller's context is done, workers that were sending
+		// a solution stopped without an error.
+		return sr.stopped(ctx.Err())
+	}
+	return nil
+}
+
+// run calls work for every cell with a pool of goroutines, until ctx is
+// done. The first error of work cancels ctx and is returned.
+func (s *ParallelSolver) run(ctx context.Context, cancel context.CancelFunc, cells []Position, work func(Position) error) error {
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	branches := make(chan Position)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range branches {
+				if err := work(p); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+send:
+	for _, p := range cells {
+		select {
+		case branches <- p:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(branches)
+	wg.Wait()
+	return firstErr
+}
+
+// Count counts the solutions like Enumerate, but searches the branches
+// with the BitSolver, which does not build boards.
+func (s *ParallelSolver) Count(sr *Search, g *Game, limit int) (int, error) {
+	sr.setPhase("prepare")
+	bg, err := NewBitGame(g)
+	if err != nil {
+		// Boards too large for a BitBoard are counted by enumerating.
+		var n int
+		err := s.search(sr, g, func(*Board) bool {
+			n++
+			return limit <= 0 || n < limit
+		})
+		return n, err
+	}
+	areas := g.sortedAreas()
+	sr.setPhase("search")
+	if len(areas) == 0 {
+		return 1, nil
+	}
+	if err := sr.enter(0); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(sr.ctx)
+	defer cancel()
+	sub := sr.withContext(ctx)
+
+	var n atomic.Int64
+	var bs BitSolver
+	err = s.run(ctx, cancel, areas[len(areas)-1].Cells, func(p Position) error {
+		b, err := bg.PlaceQueen(BitBoard{}, p[0], p[1])
+		if err != nil {
+			return err
+		}
+		sub.place()
+		_, err = bs.solveBoard(sub, bg, b, len(bg.areas)*bg.Stars-1, func(*BitGame, BitBoard) (bool, error) {
+			if c := n.Add(1); limit > 0 && c >= int64(limit) {
+				cancel()
+				return false, nil
+			}
+			return true, nil
+		})
+		return err
+	})
+	count := int(n.Load())
+	if limit > 0 && count >= limit {
+		// Workers stopped by the limit return errors of ctx.
+		return limit, nil
+	}
+	if err != nil {
+		return count, err
+	}
+	if ctx.Err() != nil {
+		return count, sr.stopped(ctx.Err())
+	}
+	return count, nil
+}
+
+// branch sends the solutions with a queen on p to solutions.
+func (s *ParallelSolver) branch(sr *Search, g *Game, areas []Area, p Position, solutions chan<- *Board) error {
+	b := g.NewBoard()
+	defer g.BoardPool.Put(b)
+	sr.place()
+	if err := g.PlaceQueen(b, p[0], p[1]); err != nil {
+		return err
+	}
+	var as AreaSolver
+	_, err := as.enumerate(sr, g, areas, b, len(areas)*g.stars()-1, func(sol *Board) bool {
+		select {
+		case solutions <- sol:
+			return true
+		case <-sr.ctx.Done():
+			return false
+		}
+	})
+	return err
+}
@@ -0,0 +1,77 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParallelSolverCount(t *testing.T) {
+	want := map[int]int{4: 2, 5: 14, 6: 90}
+
+	for _, workers := range []int{1, 4, 0} {
+		for n, w := range want {
+			g := rowAreas(n)
+			got, err := g.CountSolutions(&ParallelSolver{Workers: workers}, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != w {
+				t.Errorf("%d workers: size %d: got %d solutions, want %d", workers, n, got, w)
+			}
+		}
+	}
+}
+
+func TestParallelSolver(t *testing.T) {
+	for _, name := range []string{"../../2025-4-22.txt", "../../2025-04-23.txt", "../../2025-04-24.txt"} {
+		t.Run(name, func(t *testing.T) {
+			g := loadGame(t, name)
+			want, _, err := g.Solve(&AreaSolver{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := g.Solve(&ParallelSolver{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalFields(got, want) {
+				got.Print()
+				t.Fatal("solutions differ")
+			}
+		})
+	}
+
+	// The first solution stops the search of a board with very many.
+	g := rowAreas(14)
+	if _, _, err := g.Solve(&ParallelSolver{}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := g.CountSolutions(&ParallelSolver{}, 3)
+	if err != nil || n != 3 {
+		t.Errorf("got %d solutions, %v, want 3", n, err)
+	}
+}
+
+func TestParallelSolverNoSolution(t *testing.T) {
+	g := rowAreas(3)
+	if _, _, err := g.Solve(&ParallelSolver{}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("got error %v, want %v", err, ErrNoSolution)
+	}
+}
+
+func TestParallelSolverContext(t *testing.T) {
+	g := rowAreas(14)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := g.CountSolutionsContext(ctx, &ParallelSolver{}, 0, Budget{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	_, _, err := g.SolveContext(context.Background(), &ParallelSolver{}, Budget{Nodes: 5})
+	var se *StoppedError
+	if !errors.As(err, &se) || !errors.Is(err, ErrBudget) {
+		t.Errorf("got error %v, want %v", err, ErrBudget)
+	}
+}
@@ -0,0 +1,189 @@
+package board1
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var ErrSolved = errors.New("position is already solved")
+
+// Position files hold one row per line, with one character per field.
+const (
+	positionEmpty   = '.'
+	positionBlocked = 'X'
+	positionQueen   = 'Q'
+)
+
+// LoadPosition reads a position of a game with rows and cols.
+// Q is a queen, X a blocked field and . an empty field.
+// Spaces, tabs and empty lines are ignored, like in LoadAreas.
+func LoadPosition(r io.Reader, rows, cols int) (*Board, error) {
+	b := &Board{
+		Fields: make([]State, rows*cols),
+		Rows:   rows,
+		Cols:   cols,
+	}
+
+	s := bufio.NewScanner(r)
+	var row int
+	for s.Scan() {
+		line := s.Text()
+		line = strings.ReplaceAll(line, " ", "")
+		line = strings.ReplaceAll(line, "\t", "")
+		if line == "" {
+			continue
+		}
+		if row >= rows {
+			return nil, fmt.Errorf("position has more than %d rows", rows)
+		}
+		var col int
+		for _, c := range line {
+			if col >= cols {
+				return nil, fmt.Errorf("row %d has more than %d fields", row+1, cols)
+			}
+			switch c {
+			case positionEmpty:
+				b.Put(row, col, Empty)
+			case positionBlocked, 'x':
+				b.Put(row, col, Blocked)
+			case positionQueen, 'q':
+				b.Put(row, col, Queen)
+			default:
+				return nil, fmt.Errorf("row %d, field %d: unknown state %q", row+1, col+1, c)
+			}
+			col++
+		}
+		if col != cols {
+			return nil, fmt.Errorf("row %d has %d fields, want %d", row+1, col, cols)
+		}
+		row++
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if row != rows {
+		return nil, fmt.Errorf("position has %d rows, want %d", row, rows)
+	}
+	return b, nil
+}
+
+// WritePosition writes b in the format read by LoadPosition.
+func WritePosition(w io.Writer, b *Board) error {
+	bw := bufio.NewWriter(w)
+	for i := range b.Rows {
+		for j := range b.Cols {
+			if j > 0 {
+				bw.WriteByte('\t')
+			}
+			switch b.Get(i, j) {
+			case Blocked:
+				bw.WriteByte(positionBlocked)
+			case Queen:
+				bw.WriteByte(positionQueen)
+			default:
+				bw.WriteByte(positionEmpty)
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Hint returns the next step from position b with DefaultRules.
+func Hint(g *Game, b *Board) (Step, error) {
+	return HintWith(g, b, DefaultRules)
+}
+
+// HintWith returns the single next step from position b, without
+// revealing the rest of the solution. Fields attacked by the queens on b
+// that are not marked yet are hinted first. b is not changed.
+//
+// It returns ErrSolved if b is solved, an error wrapping ErrNoSolution if
+// b can no longer lead to a solution and ErrStuck if no rule applies.
+func HintWith(g *Game, b *Board, rules RuleSet) (Step, error) {
+	if b.Rows != g.Rows || b.Cols != g.Cols {
+		return Step{}, fmt.Errorf("position of %dx%d does not fit the board of %dx%d", b.Rows, b.Cols, g.Rows, g.Cols)
+	}
+	nb := g.NewBoard()
+	defer g.BoardPool.Put(nb)
+
+	// Place the queens of the position, this blocks what they attack.
+	for i, s := range b.Fields {
+		if s != Queen {
+			continue
+		}
+		p := Position{i / b.Cols, i % b.Cols}
+		if nb.Get(p[0], p[1]) != Empty {
+			return Step{}, fmt.Errorf("queen at %s is attacked: %w", FieldName(p), ErrNoSolution)
+		}
+		g.putQueen(nb, p[0], p[1])
+
+		var attacked []Position
+		for j, s := range nb.Fields {
+			if s == Blocked && b.Fields[j] == Empty {
+				attacked = append(attacked, Position{j / b.Cols, j % b.Cols})
+			}
+		}
+		if len(attacked) > 0 {
+			return Step{
+				Rule:    "attacked",
+				Reason:  fmt.Sprintf("fields attacked by queen at %s", FieldName(p)),
+				Blocked: attacked,
+				Board:   markBlocked(b, attacked),
+			}, nil
+		}
+	}
+	for i, s := range b.Fields {
+		if s == Blocked {
+			nb.Fields[i] = Blocked
+		}
+	}
+
+	units := g.units()
+	if err := contradiction(nb, units); err != nil {
+		return Step{}, fmt.Errorf("%w: %w", ErrNoSolution, err)
+	}
+	if solved(nb, units) {
+		return Step{}, ErrSolved
+	}
+	if !g.solvable(nb, units) {
+		return Step{}, fmt.Errorf("position: %w", ErrNoSolution)
+	}
+
+	s, ok := rules.Apply(g, nb)
+	if !ok {
+		return Step{}, ErrStuck
+	}
+	if err := g.apply(nb, &s); err != nil {
+		return Step{}, err
+	}
+	s.Board = nb.Clone()
+	return s, nil
+}
+
+// markBlocked returns a copy of b with fields blocked.
+func markBlocked(b *Board, fields []Position) *Board {
+	nb := b.Clone()
+	for _, p := range fields {
+		nb.Put(p[0], p[1], Blocked)
+	}
+	return nb
+}
+
+// solvable reports whether b still leads to a solution.
+func (g *Game) solvable(b *Board, units []unit) bool {
+	nb := g.BoardPool.Get()
+	nb.CopyFrom(b)
+	s := &PropagationSolver{}
+	res, err := s.solveBoard(NewSearch(context.Background(), Budget{}), g, nb, units, 0)
+	if err != nil {
+		g.BoardPool.Put(nb)
+		return false
+	}
+	g.BoardPool.Put(res)
+	return true
+}
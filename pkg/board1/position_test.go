@@ -0,0 +1,100 @@
+package board1
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPosition(t *testing.T) {
+	const position = `Q	X	X
+X	X	.
+.	.	.
+`
+	b, err := LoadPosition(strings.NewReader(position), 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Get(0, 0) != Queen || b.Get(1, 1) != Blocked || b.Get(2, 2) != Empty {
+		t.Error("unexpected states")
+	}
+
+	var buf bytes.Buffer
+	if err := WritePosition(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != position {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), position)
+	}
+
+	if _, err := LoadPosition(strings.NewReader("Q..\n...\n"), 3, 3); err == nil {
+		t.Error("expected an error for a missing row")
+	}
+	if _, err := LoadPosition(strings.NewReader("Q.?\n...\n...\n"), 3, 3); err == nil {
+		t.Error("expected an error for an unknown state")
+	}
+}
+
+func TestHint(t *testing.T) {
+	g := loadGame(t, "../../2025-04-24.txt")
+	steps, err := Explain(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// From the start the hint is the first step of the explanation.
+	b := &Board{Fields: make([]State, g.Rows*g.Cols), Rows: g.Rows, Cols: g.Cols}
+	s, err := Hint(g, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Reason != steps[0].Reason {
+		t.Errorf("got hint %q, want %q", s.Reason, steps[0].Reason)
+	}
+	if equalFields(s.Board, steps[len(steps)-1].Board) {
+		t.Error("hint reveals the solution")
+	}
+
+	// A queen without its blocked fields is marked first.
+	q := steps[len(steps)-1].Board
+	var queen Position
+	for i, f := range q.Fields {
+		if f == Queen {
+			queen = Position{i / q.Cols, i % q.Cols}
+			break
+		}
+	}
+	b.Put(queen[0], queen[1], Queen)
+	s, err = Hint(g, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Rule != "attacked" {
+		t.Errorf("got rule %q, want attacked", s.Rule)
+	}
+
+	// The solution needs no hints.
+	if _, err := Hint(g, q); err != ErrSolved {
+		t.Errorf("got error %v, want %v", err, ErrSolved)
+	}
+}
+
+func TestHintWrongQueen(t *testing.T) {
+	g := loadGame(t, "../../2025-04-24.txt")
+	sol, _, err := g.Solve(&AreaSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Put a queen, and what it attacks, on an empty field of the solution.
+	b := &Board{Fields: make([]State, g.Rows*g.Cols), Rows: g.Rows, Cols: g.Cols}
+	for i, f := range sol.Fields {
+		if f != Queen {
+			g.putQueen(b, i/b.Cols, i%b.Cols)
+			break
+		}
+	}
+	if _, err = Hint(g, b); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("got error %v, want %v", err, ErrNoSolution)
+	}
+}
@@ -0,0 +1,152 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Tier is the difficulty tier of a puzzle.
+type Tier int
+
+const (
+	Easy Tier = iota
+	Medium
+	Hard
+	Expert
+	// Guessing is the tier of puzzles the rules cannot solve.
+	Guessing
+)
+
+func (t Tier) String() string {
+	switch t {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	case Expert:
+		return "expert"
+	case Guessing:
+		return "guessing"
+	default:
+		return "?"
+	}
+}
+
+// ParseTier returns the tier with name s.
+func ParseTier(s string) (Tier, error) {
+	for t := Easy; t <= Guessing; t++ {
+		if strings.EqualFold(s, t.String()) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown tier %q", s)
+}
+
+// Rating is the difficulty of a puzzle.
+type Rating struct {
+	Tier  Tier
+	Score int
+
+	// HardestRule is the name of the hardest rule needed.
+	HardestRule string
+	// Hardest is the difficulty of the hardest rule needed.
+	Hardest int
+	// Steps is the number of deduction steps.
+	Steps int
+	// Branches is the number of guesses needed after the rules got stuck.
+	Branches int
+
+	// Stats are the counts of the search of the AreaSolver, without
+	// the guesses counted in Branches.
+	Stats SolveStats
+
+	// NoSolution is set when the puzzle cannot be solved.
+	NoSolution bool
+}
+
+func (r Rating) String() string {
+	return fmt.Sprintf("%s (score %d, hardest rule %q, steps %d, branches %d, nodes %d, placements %d)",
+		r.Tier, r.Score, r.HardestRule, r.Steps, r.Branches, r.Stats.Nodes, r.Stats.Placements)
+}
+
+// Rate scores how hard g is for a human, using DefaultRules.
+//
+// A puzzle that can be solved by the rules scores 25 points per rule level
+// above the first, plus a point per step, up to 24. Puzzles that need
+// guessing score from 100 up, depending on the branches and the placements
+// of the search, and get the tier Guessing, like puzzles without solution.
+func Rate(g *Game) Rating {
+	r, _ := RateContext(context.Background(), g, Budget{})
+	return r
+}
+
+// RateContext rates g like Rate, but stops like SolveContext. The budget
+// applies to the whole rating, not to each search on its own.
+func RateContext(ctx context.Context, g *Game, budget Budget) (Rating, error) {
+	var r Rating
+	s := NewSearch(ctx, budget).bind(g)
+	err := rate(s, g, &r)
+	s.finish()
+	return r, err
+}
+
+func rate(sr *Search, g *Game, r *Rating) error {
+	var se *StoppedError
+	if err := sr.ctx.Err(); err != nil {
+		return sr.stopped(err)
+	}
+
+	// The search counts are a signal for every puzzle.
+	_, err := (&AreaSolver{}).Solve(sr, g)
+	r.Stats = sr.Stats()
+	if errors.As(err, &se) {
+		return err
+	}
+	if err != nil {
+		r.NoSolution = true
+		r.Tier, r.Score = Guessing, 100+bits.Len64(uint64(r.Stats.Placements))
+		return nil
+	}
+
+	steps, err := explain(sr, g, DefaultRules)
+	if errors.As(err, &se) {
+		return err
+	}
+	r.Steps = len(steps)
+	for _, s := range steps {
+		if s.Difficulty > r.Hardest {
+			r.Hardest, r.HardestRule = s.Difficulty, s.Rule
+		}
+	}
+	if errors.Is(err, ErrStuck) {
+		if _, err := (&PropagationSolver{}).Solve(sr, g); errors.As(err, &se) {
+			return err
+		}
+		r.Branches = int(sr.Stats().Branches)
+		r.Score = 100 + 25*r.Branches + bits.Len64(uint64(r.Stats.Placements))
+	} else {
+		r.Score = 25*max(r.Hardest-1, 0) + min(r.Steps, 24)
+	}
+	r.Tier = tierOf(r.Score)
+	return nil
+}
+
+func tierOf(score int) Tier {
+	switch {
+	case score < 25:
+		return Easy
+	case score < 50:
+		return Medium
+	case score < 75:
+		return Hard
+	case score < 100:
+		return Expert
+	default:
+		return Guessing
+	}
+}
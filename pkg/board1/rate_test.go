@@ -0,0 +1,74 @@
+package board1
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		name string
+		tier Tier
+	}{
+		{name: "../../2025-04-23.txt", tier: Easy},
+		{name: "../../2025-4-22.txt", tier: Hard},
+		{name: "../../2025-04-24.txt", tier: Expert},
+		{name: "../../cmd/queens/board.txt", tier: Guessing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Rate(loadGame(t, tt.name))
+			t.Log(r)
+			if r.Tier != tt.tier {
+				t.Errorf("got tier %s, want %s", r.Tier, tt.tier)
+			}
+		})
+	}
+}
+
+func TestRateNeedsGuessing(t *testing.T) {
+	r := Rate(loadGame(t, "../../cmd/queens/board.txt"))
+	if r.Branches == 0 || r.Score < 100 {
+		t.Errorf("got %d branches and score %d, want branches and a score from 100", r.Branches, r.Score)
+	}
+	// The hardest rule solves this one without guessing.
+	logic := Rate(loadGame(t, "../../2025-04-24.txt"))
+	if logic.Branches > 0 || logic.Hardest != 4 {
+		t.Fatalf("got rating %s, want the hardest rule without branches", logic)
+	}
+	if logic.Tier == r.Tier {
+		t.Errorf("hard logic and guessing both got tier %s", r.Tier)
+	}
+}
+
+func TestParseTier(t *testing.T) {
+	for tier := Easy; tier <= Guessing; tier++ {
+		got, err := ParseTier(tier.String())
+		if err != nil || got != tier {
+			t.Errorf("ParseTier(%q) = %v, %v", tier.String(), got, err)
+		}
+	}
+	if _, err := ParseTier("impossible"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRateContextBudget(t *testing.T) {
+	g := loadGame(t, "../../cmd/queens/board.txt")
+	_, stats, err := g.Solve(&AreaSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The AreaSolver fits in the budget, the guessing that follows does not.
+	r, err := RateContext(context.Background(), g, Budget{Nodes: int(stats.Nodes) + 1})
+	if !errors.Is(err, ErrBudget) {
+		t.Fatalf("got rating %s and error %v, want %v", r, err, ErrBudget)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RateContext(ctx, g, Budget{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,282 @@
+package board1
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Rule is a logical deduction a human can make on a board.
+type Rule interface {
+	// Name returns the short name of the rule.
+	Name() string
+	// Difficulty returns how hard the rule is for a human, from 1 up.
+	Difficulty() int
+	// Apply returns the first step the rule finds on b, false if it finds none.
+	// Apply must not change b.
+	Apply(g *Game, b *Board) (Step, bool)
+}
+
+// RuleSet is an ordered set of rules, simpler rules go first.
+type RuleSet []Rule
+
+// DefaultRules are the rules used by Explain and the PropagationSolver.
+var DefaultRules = RuleSet{
+	SingleEmptyRule{},
+	ConfinedRule{},
+	ConfinedGroupRule{},
+	WouldEmptyRule{},
+}
+
+// Apply returns the step of the first rule that applies.
+func (rs RuleSet) Apply(g *Game, b *Board) (Step, bool) {
+	for _, r := range rs {
+		if s, ok := r.Apply(g, b); ok {
+			s.Difficulty = r.Difficulty()
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// SingleEmptyRule places a queen on the only empty field of a unit, or
+// with more stars on an empty field of a unit with as many empty fields
+// as queens it needs.
+type SingleEmptyRule struct{}
+
+func (SingleEmptyRule) Name() string { return "single empty field" }
+
+func (SingleEmptyRule) Difficulty() int { return 1 }
+
+func (r SingleEmptyRule) Apply(g *Game, b *Board) (Step, bool) {
+	for _, u := range g.units() {
+		empty, need := u.scan(b)
+		if need == 0 || len(empty) != need {
+			continue
+		}
+		reason := fmt.Sprintf("%s has one empty field left, queen at %s", u, FieldName(empty[0]))
+		if need > 1 {
+			reason = fmt.Sprintf("%s has %d empty fields left for %d queens, queen at %s", u, need, need, FieldName(empty[0]))
+		}
+		return Step{
+			Rule:   r.Name(),
+			Reason: reason,
+			Queen:  empty[0],
+		}, true
+	}
+	return Step{}, false
+}
+
+// ConfinedRule finds a unit whose empty fields all lie in another unit,
+// for example an area confined to a row. The queen of that row must be
+// in the area, so the other empty fields of the row are blocked. With more
+// stars the area must need at least as many queens as the row.
+type ConfinedRule struct{}
+
+func (ConfinedRule) Name() string { return "confined" }
+
+func (ConfinedRule) Difficulty() int { return 2 }
+
+func (r ConfinedRule) Apply(g *Game, b *Board) (Step, bool) {
+	units := g.units()
+	for _, u := range units {
+		empty, need := u.scan(b)
+		if need == 0 || len(empty) == 0 {
+			continue
+		}
+		for _, o := range units {
+			if o.kind == u.kind || !contains(o.cells, empty...) {
+				continue
+			}
+			if _, oNeed := o.scan(b); need < oNeed {
+				continue
+			}
+			var blocked []Position
+			for _, p := range o.cells {
+				if b.Get(p[0], p[1]) == Empty && !contains(empty, p) {
+					blocked = append(blocked, p)
+				}
+			}
+			if len(blocked) > 0 {
+				return Step{
+					Rule:    r.Name(),
+					Reason:  fmt.Sprintf("%s confined to %s", u, o),
+					Blocked: blocked,
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// maxGroupLines limits the number of units ConfinedGroupRule considers,
+// it tries every subset of them.
+const maxGroupLines = 16
+
+// ConfinedGroupRule finds n units whose empty fields all lie in n units of
+// another kind, for example two areas that only have empty fields in
+// two rows. The queens of those rows must then be in these areas, so the
+// other empty fields of the rows are blocked. With more stars the units
+// must need as many queens as the units of the other kind.
+type ConfinedGroupRule struct{}
+
+func (ConfinedGroupRule) Name() string { return "confined group" }
+
+func (ConfinedGroupRule) Difficulty() int { return 3 }
+
+func (r ConfinedGroupRule) Apply(g *Game, b *Board) (Step, bool) {
+	kinds := map[string][]unit{}
+	for _, u := range g.units() {
+		kinds[u.kind] = append(kinds[u.kind], u)
+	}
+	for _, inner := range []string{"area", "row", "column"} {
+		for _, outer := range []string{"row", "column", "area"} {
+			if inner == outer || len(kinds[outer]) > maxGroupLines {
+				continue
+			}
+			if s, ok := r.apply(b, kinds[inner], kinds[outer]); ok {
+				return s, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+func (r ConfinedGroupRule) apply(b *Board, inner, outer []unit) (Step, bool) {
+	// index of the outer unit of each field
+	index := map[[2]int]int{}
+	var open uint64
+	outerNeed := make([]int, len(outer))
+	for i, o := range outer {
+		for _, p := range o.cells {
+			index[[2]int{p[0], p[1]}] = i
+		}
+		if _, need := o.scan(b); need > 0 {
+			open |= 1 << i
+			outerNeed[i] = need
+		}
+	}
+	// masks holds the outer units the empty fields of each open inner unit lie in.
+	var masks []uint64
+	var openInner []unit
+	var empties [][]Position
+	var innerNeed []int
+	for _, u := range inner {
+		empty, need := u.scan(b)
+		if need == 0 {
+			continue
+		}
+		var m uint64
+		for _, p := range empty {
+			m |= 1 << index[[2]int{p[0], p[1]}]
+		}
+		masks = append(masks, m)
+		openInner = append(openInner, u)
+		empties = append(empties, empty)
+		innerNeed = append(innerNeed, need)
+	}
+	g := group{open: open, masks: masks, inner: openInner, empties: empties, innerNeed: innerNeed, outerNeed: outerNeed, outer: outer}
+
+	// Try small groups first, they are easier to spot.
+	for n := 2; n < bits.OnesCount64(open); n++ {
+		if s, ok := r.applyN(b, n, g); ok {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// group holds the open units of ConfinedGroupRule.apply.
+type group struct {
+	open      uint64
+	masks     []uint64
+	inner     []unit
+	empties   [][]Position
+	innerNeed []int
+	outerNeed []int
+	outer     []unit
+}
+
+func (r ConfinedGroupRule) applyN(b *Board, n int, g group) (Step, bool) {
+	for set := g.open; set > 0; set = (set - 1) & g.open {
+		if bits.OnesCount64(set) != n {
+			continue
+		}
+		var cells []Position
+		var names []string
+		var need int
+		for i, m := range g.masks {
+			if m&^set == 0 {
+				cells = append(cells, g.empties[i]...)
+				names = append(names, g.inner[i].String())
+				need += g.innerNeed[i]
+			}
+		}
+		for i := range g.outer {
+			if set&(1<<i) != 0 {
+				need -= g.outerNeed[i]
+			}
+		}
+		if need != 0 {
+			continue
+		}
+		var blocked []Position
+		var lines []string
+		for i, o := range g.outer {
+			if set&(1<<i) == 0 {
+				continue
+			}
+			lines = append(lines, o.String())
+			for _, p := range o.cells {
+				if b.Get(p[0], p[1]) == Empty && !contains(cells, p) {
+					blocked = append(blocked, p)
+				}
+			}
+		}
+		if len(blocked) > 0 {
+			return Step{
+				Rule:    r.Name(),
+				Reason:  fmt.Sprintf("%s confined to %s", strings.Join(names, ", "), strings.Join(lines, ", ")),
+				Blocked: blocked,
+			}, true
+		}
+	}
+	return Step{}, false
+}
+
+// WouldEmptyRule blocks an empty field when a queen on it
+// leaves another unit with fewer empty fields than queens it needs.
+type WouldEmptyRule struct{}
+
+func (WouldEmptyRule) Name() string { return "would empty unit" }
+
+func (WouldEmptyRule) Difficulty() int { return 4 }
+
+func (r WouldEmptyRule) Apply(g *Game, b *Board) (Step, bool) {
+	units := g.units()
+	nb := g.BoardPool.Get()
+	defer g.BoardPool.Put(nb)
+
+	for i, s := range b.Fields {
+		if s != Empty {
+			continue
+		}
+		p := Position{i / b.Cols, i % b.Cols}
+		nb.CopyFrom(b)
+		g.putQueen(nb, p[0], p[1])
+		for _, u := range units {
+			if empty, need := u.scan(nb); len(empty) < need {
+				reason := fmt.Sprintf("queen at %s would leave %s without empty field", FieldName(p), u)
+				if len(empty) > 0 {
+					reason = fmt.Sprintf("queen at %s would leave %s with %d empty fields for %d queens", FieldName(p), u, len(empty), need)
+				}
+				return Step{
+					Rule:    r.Name(),
+					Reason:  reason,
+					Blocked: []Position{p},
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
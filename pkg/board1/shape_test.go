@@ -0,0 +1,236 @@
+package board1
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// shapes are boards that are not square or have holes, with the number
+// of solutions counted by brute force.
+var shapes = []struct {
+	name  string
+	board string
+	count int
+}{
+	{
+		name: "wide",
+		board: `0001122
+0311122
+3331222
+3444442
+3333442
+`,
+		count: 56,
+	},
+	{
+		name: "tall",
+		board: `00333
+03343
+01343
+11143
+11244
+22244
+22222
+`,
+		count: 56,
+	},
+	{
+		name: "holes",
+		board: `0.111
+00213
+.2233
+44433
+4.443
+`,
+		count: 1,
+	},
+	{
+		name: "wide with holes",
+		board: `..0011
+.00111
+222.13
+2.4433
+244..3
+`,
+		count: 15,
+	},
+}
+
+// checkShape fails if b is not a solution of g: every area must hold
+// g.Stars queens and every row and column at most that many, exactly if
+// there are as many of them as areas. Holes stay holes and no queens may
+// block each other by g.Adjacency. Rows and columns with only holes stay
+// empty.
+func checkShape(t *testing.T, g *Game, b *Board) {
+	t.Helper()
+	k := g.stars()
+	exactRows, exactCols := g.exactLines()
+	hole := g.holeMask()
+	fieldsInRow, fieldsInColumn := make([]bool, g.Rows), make([]bool, g.Cols)
+	for i, h := range hole {
+		if !h {
+			fieldsInRow[i/g.Cols], fieldsInColumn[i%g.Cols] = true, true
+		}
+	}
+	for i := range g.Rows {
+		if n := b.queensInRow(i); n > k || exactRows && fieldsInRow[i] && n != k {
+			t.Errorf("row %d has %d queens", i+1, n)
+		}
+	}
+	for i := range g.Cols {
+		if n := b.queensInColumn(i); n > k || exactCols && fieldsInColumn[i] && n != k {
+			t.Errorf("column %d has %d queens", i+1, n)
+		}
+	}
+	for i, a := range g.Areas {
+		if n := b.queensIn(a.Cells); n != k {
+			t.Errorf("%s has %d queens, want %d", areaName(g.Areas, i), n, k)
+		}
+	}
+	for _, h := range g.Holes {
+		if s := b.Get(h[0], h[1]); s != Hole {
+			t.Errorf("hole %s is %q", FieldName(h), s)
+		}
+	}
+	for row := range b.Rows {
+		for col := range b.Cols {
+			if b.Get(row, col) != Queen {
+				continue
+			}
+			for _, d := range g.Adjacency.Offsets() {
+				r, c := row+d[0], col+d[1]
+				if d[0] < 0 || d[0] == 0 && d[1] < 0 {
+					// Checked from the other queen.
+					continue
+				}
+				if r < b.Rows && c >= 0 && c < b.Cols && b.Get(r, c) == Queen {
+					t.Errorf("queens on (%d, %d) and (%d, %d) touch", row+1, col+1, r+1, c+1)
+				}
+			}
+		}
+	}
+}
+
+func TestShapes(t *testing.T) {
+	solvers := []Solver{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &PropagationSolver{}, &ParallelSolver{}}
+	for _, tt := range shapes {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := LoadGame(strings.NewReader(tt.board))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range solvers {
+				b, _, err := g.Solve(s)
+				if err != nil {
+					t.Fatalf("%T: %v", s, err)
+				}
+				checkShape(t, g, b)
+
+				e, ok := s.(Enumerator)
+				if !ok {
+					continue
+				}
+				n, err := g.CountSolutions(e, 0)
+				if err != nil {
+					t.Fatalf("%T: %v", s, err)
+				}
+				if n != tt.count {
+					t.Errorf("%T: got %d solutions, want %d", s, n, tt.count)
+				}
+			}
+		})
+	}
+}
+
+func TestShapesWithoutAreas(t *testing.T) {
+	tall := NewGame(5, 3)
+	wide := NewGame(3, 5)
+	// A row of holes leaves three rows for four columns.
+	holes := NewGame(4, 4)
+	for col := range 4 {
+		holes.Holes = append(holes.Holes, Position{1, col})
+	}
+	tests := []struct {
+		name  string
+		game  *Game
+		count int
+	}{
+		{name: "tall", game: tall, count: 60},
+		{name: "wide", game: wide, count: 60},
+		{name: "row of holes", game: holes, count: 24},
+	}
+	solvers := []Solver{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &PropagationSolver{}, &ParallelSolver{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.game
+			g.Adjacency = NoAdjacency
+			for _, s := range solvers {
+				b, _, err := g.Solve(s)
+				if err != nil {
+					t.Fatalf("%T: %v", s, err)
+				}
+				checkShape(t, g, b)
+
+				e, ok := s.(Enumerator)
+				if !ok {
+					continue
+				}
+				n, err := g.CountSolutions(e, 0)
+				if err != nil {
+					t.Fatalf("%T: %v", s, err)
+				}
+				if n != tt.count {
+					t.Errorf("%T: got %d solutions, want %d", s, n, tt.count)
+				}
+			}
+		})
+	}
+}
+
+func TestShapeExplain(t *testing.T) {
+	// The board with holes has a unique solution.
+	g, err := LoadGame(strings.NewReader(shapes[2].board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := Explain(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkShape(t, g, steps[len(steps)-1].Board)
+}
+
+func TestLoadGameHoles(t *testing.T) {
+	const board = "0 . 1\n0 1 1\n"
+	g, err := LoadGame(strings.NewReader(board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Rows != 2 || g.Cols != 3 {
+		t.Fatalf("got a board of %dx%d, want 2x3", g.Rows, g.Cols)
+	}
+	if len(g.Holes) != 1 || !slices.Equal(g.Holes[0], Position{0, 1}) {
+		t.Errorf("got holes %v, want [[0 1]]", g.Holes)
+	}
+	if _, _, err := LoadAreas(strings.NewReader(board)); err == nil {
+		t.Error("LoadAreas loaded a board with holes")
+	}
+
+	var sb strings.Builder
+	if err := WriteAreas(&sb, g.Rows, g.Cols, g.Areas); err != nil {
+		t.Fatal(err)
+	}
+	if want := "0\t.\t1\n0\t1\t1\n"; sb.String() != want {
+		t.Errorf("got %q, want %q", sb.String(), want)
+	}
+
+	p := NewPuzzle(g)
+	b, err := p.Position(p.Game())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := b.Get(0, 1); s != Hole {
+		t.Errorf("got %q on the hole, want %q", s, Hole)
+	}
+}
@@ -0,0 +1,135 @@
+package board1
+
+import (
+	"context"
+	"iter"
+)
+
+// Enumerator is implemented by solvers that can find every solution of a game,
+// not just the first one.
+type Enumerator interface {
+	// Enumerate calls yield for every solution until yield returns false.
+	// The boards passed to yield are owned by the caller, they are not
+	// returned to the BoardPool.
+	Enumerate(s *Search, g *Game, yield func(*Board) bool) error
+}
+
+// Counter is implemented by enumerators that count solutions faster than
+// by enumerating their boards. CountSolutions uses it when it can.
+type Counter interface {
+	// Count counts the solutions, it stops when limit is reached.
+	// A limit <= 0 counts all solutions.
+	Count(s *Search, g *Game, limit int) (int, error)
+}
+
+// Solutions returns an iterator over all solutions found by e, with a nil
+// error. If the search fails, the last pair holds a nil board and the error.
+func (g *Game) Solutions(e Enumerator) iter.Seq2[*Board, error] {
+	return func(yield func(*Board, error) bool) {
+		stopped := false
+		err := e.Enumerate(NewSearch(context.Background(), Budget{}).bind(g), g, func(b *Board) bool {
+			stopped = !yield(b, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// CountSolutions counts the solutions found by e.
+// It stops counting when limit is reached, a limit <= 0 counts all solutions.
+func (g *Game) CountSolutions(e Enumerator, limit int) (int, error) {
+	n, _, err := g.CountSolutionsContext(context.Background(), e, limit, Budget{})
+	return n, err
+}
+
+func (s *AreaSolver) Enumerate(sr *Search, g *Game, yield func(*Board) bool) error {
+	b := g.NewBoard()
+	defer g.BoardPool.Put(b)
+
+	// Sort the areas
+	sr.setPhase("sort")
+	areas := g.sortedAreas()
+
+	sr.setPhase("search")
+	_, err := s.enumerate(sr, g, areas, b, len(areas)*g.stars(), yield)
+	return err
+}
+
+// enumerate returns false when yield asked to stop.
+func (s *AreaSolver) enumerate(sr *Search, g *Game, areas []Area, b *Board, n int, yield func(*Board) bool) (bool, error) {
+	if n == 0 {
+		return yield(b.Clone()), nil
+	}
+	k := g.stars()
+	if err := sr.enter(len(areas)*k - n); err != nil {
+		return false, err
+	}
+
+	placed := false
+	for _, p := range after(b, areas[(n-1)/k].Cells) {
+		row := p[0]
+		col := p[1]
+		if b.Get(row, col) != Empty {
+			continue
+		}
+
+		nb := g.BoardPool.Get()
+		nb.CopyFrom(b)
+		sr.place()
+		placed = true
+		if err := g.PlaceQueen(nb, row, col); err != nil {
+			return false, err
+		}
+		more, err := s.enumerate(sr, g, areas, nb, n-1, yield)
+		g.BoardPool.Put(nb)
+		if err != nil || !more {
+			return false, err
+		}
+	}
+	if !placed {
+		sr.backtrack()
+	}
+	return true, nil
+}
+
+func (s *SimpleSolver) Enumerate(sr *Search, g *Game, yield func(*Board) bool) error {
+	b := g.NewBoard()
+	defer g.BoardPool.Put(b)
+
+	sr.setPhase("search")
+	_, err := s.enumerate(sr, g, b, g.Queens(), 0, 0, yield)
+	return err
+}
+
+// enumerate only places queens at or after row, col so every solution
+// is found exactly once.
+func (s *SimpleSolver) enumerate(sr *Search, g *Game, b *Board, n, row, col int, yield func(*Board) bool) (bool, error) {
+	if n == 0 {
+		return yield(b.Clone()), nil
+	}
+	if err := sr.enter(g.Queens() - n); err != nil {
+		return false, err
+	}
+
+	placed := false
+	for row, col, err := b.FindEmpty(row, col); err == nil; row, col, err = b.FindNextEmpty(row, col) {
+		nb := g.BoardPool.Get()
+		nb.CopyFrom(b)
+		sr.place()
+		placed = true
+		if err := g.PlaceQueen(nb, row, col); err != nil {
+			return false, err
+		}
+		more, err := s.enumerate(sr, g, nb, n-1, row, col, yield)
+		g.BoardPool.Put(nb)
+		if err != nil || !more {
+			return false, err
+		}
+	}
+	if !placed {
+		sr.backtrack()
+	}
+	return true, nil
+}
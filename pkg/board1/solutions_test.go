@@ -0,0 +1,157 @@
+package board1
+
+import (
+	"strings"
+	"testing"
+)
+
+// rowAreas returns a game where every row is an area.
+func rowAreas(n int) *Game {
+	areas := make([]Area, n)
+	for i := range n {
+		for j := range n {
+			areas[i].Cells = append(areas[i].Cells, Position{i, j})
+		}
+	}
+	return NewGame(n, n, areas...)
+}
+
+func TestCountSolutions(t *testing.T) {
+	// Permutations without adjacent neighbours, see OEIS A002464.
+	want := map[int]int{4: 2, 5: 14, 6: 90}
+
+	for _, e := range []Enumerator{&AreaSolver{}, &SimpleSolver{}} {
+		for n, w := range want {
+			g := rowAreas(n)
+			got, err := g.CountSolutions(e, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != w {
+				t.Errorf("%T: size %d: got %d solutions, want %d", e, n, got, w)
+			}
+		}
+	}
+}
+
+func TestCountSolutionsLimit(t *testing.T) {
+	g := rowAreas(6)
+	got, err := g.CountSolutions(&AreaSolver{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 10 {
+		t.Errorf("got %d solutions, want 10", got)
+	}
+}
+
+func TestSolutions(t *testing.T) {
+	const board = `0	0	1	1	1	1	2
+0	4	4	1	1	1	2
+3	4	6	6	6	1	2
+3	5	6	6	6	5	2
+3	5	6	6	6	5	2
+3	5	5	5	5	5	2
+3	3	2	2	2	2	2
+`
+	a, i, err := LoadAreas(strings.NewReader(board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGame(i, i, a...)
+
+	var boards []*Board
+	for b, err := range g.Solutions(&AreaSolver{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		boards = append(boards, b)
+	}
+	if len(boards) != 1 {
+		t.Fatalf("got %d solutions, want 1", len(boards))
+	}
+
+	// The solution must survive further use of the pool.
+	want := boards[0].Clone()
+	g.Solve(&AreaSolver{})
+	for i := range want.Fields {
+		if want.Fields[i] != boards[0].Fields[i] {
+			t.Fatal("solution changed after solving again")
+		}
+	}
+}
+
+func TestCheckUnique(t *testing.T) {
+	tests := []struct {
+		name   string
+		board  string
+		unique bool
+	}{
+		{
+			name: "unique",
+			board: `0	0	1	1	1	1	2
+0	4	4	1	1	1	2
+3	4	6	6	6	1	2
+3	5	6	6	6	5	2
+3	5	6	6	6	5	2
+3	5	5	5	5	5	2
+3	3	2	2	2	2	2
+`,
+			unique: true,
+		},
+		{
+			name: "two solutions",
+			board: `0	0	0	0	0	0	2	2	2
+1	0	0	6	0	0	0	2	2
+1	0	4	6	0	0	0	0	2
+1	0	4	6	7	8	0	2	2
+1	0	4	7	7	8	9	2	2
+1	1	4	7	7	8	9	2	2
+3	3	4	7	7	8	9	2	2
+3	3	3	7	7	8	9	2	2
+3	2	2	2	2	2	2	2	2
+`,
+			unique: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, i, err := LoadAreas(strings.NewReader(tt.board))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := CheckUnique(NewGame(i, i, a...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Unique != tt.unique {
+				t.Errorf("got unique %v, want %v", res.Unique, tt.unique)
+			}
+			if !tt.unique && len(res.Solutions) != 2 {
+				t.Errorf("got %d witnesses, want 2", len(res.Solutions))
+			}
+		})
+	}
+}
+
+func TestCheckUniqueNoSolution(t *testing.T) {
+	// Two areas, the queens of a 2x2 board always touch.
+	g := rowAreas(2)
+	if _, err := CheckUnique(g); err != ErrNoSolution {
+		t.Errorf("got error %v, want %v", err, ErrNoSolution)
+	}
+}
+
+func TestSolutionsError(t *testing.T) {
+	// A BitBoard cannot hold a board of 17x17.
+	var got error
+	for b, err := range NQueens(17).Solutions(&BitSolver{}) {
+		if b != nil {
+			t.Fatal("got a solution")
+		}
+		got = err
+	}
+	if got == nil {
+		t.Error("got no error")
+	}
+}
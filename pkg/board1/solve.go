@@ -1,28 +1,48 @@
 package board1
 
+import "errors"
+
 type AreaSolver struct {}
 
-func (s *AreaSolver) Solve(g *Game) (*Board, error) {	
-	g.solveCalled = 0
-	b := g.BoardPool.Get()
+func (s *AreaSolver) Solve(sr *Search, g *Game) (*Board, error) {	
+	b := g.NewBoard()
 	defer g.BoardPool.Put(b)
 
 	// Sort the areas
-	SortAreasReverse(g.Areas)
+	sr.setPhase("sort")
+	areas := g.sortedAreas()
 
-	return s.solveBoard(g, b, g.Cols)
+	sr.setPhase("search")
+	return s.solveBoard(sr, g, areas, b, len(areas)*g.stars())
 }
 
-func (s *AreaSolver) solveBoard(g *Game, b *Board, n int) (*Board, error) {
+// after returns the cells after the last queen on cells. The queens of an
+// area are placed in the order of its cells, so every set of queens is
+// only tried once.
+func after(b *Board, cells []Position) []Position {
+	for i := len(cells) - 1; i >= 0; i-- {
+		if b.Get(cells[i][0], cells[i][1]) == Queen {
+			return cells[i+1:]
+		}
+	}
+	return cells
+}
+
+// solveBoard places the n queens left, the stars of an area one by one.
+func (s *AreaSolver) solveBoard(sr *Search, g *Game, areas []Area, b *Board, n int) (*Board, error) {
 	if n == 0 {
 		return b, nil
 	}
-	g.solveCalled++
+	k := g.stars()
+	if err := sr.enter(len(areas)*k - n); err != nil {
+		return nil, err
+	}
 
-	// Get the last area of areas
-	a := g.Areas[n-1]
+	// Get the last area of areas without all its queens
+	a := areas[(n-1)/k]
 
-	for _, p := range a {
+	placed := false
+	for _, p := range after(b, a.Cells) {
 		row := p[0]
 		col := p[1]
 		if b.Get(row, col) != Empty {
@@ -33,51 +53,72 @@ func (s *AreaSolver) solveBoard(g *Game, b *Board, n int) (*Board, error) {
 		nb := g.BoardPool.Get()
 		nb.CopyFrom(b)
 		// Place the queen on the empty place, return on error
+		sr.place()
+		placed = true
 		if err := g.PlaceQueen(nb, row, col); err != nil {
 			return nil, err
 		}
 		// Try to solve this board
-		res, err := g.solveBoard(nb, n-1)
+		res, err := s.solveBoard(sr, g, areas, nb, n-1)
 		if err == nil {
 			return res, nil
 		}
 		// No solution found, return the board to the pool
 		g.BoardPool.pool.Put(nb)
+		if !errors.Is(err, ErrNoSolution) {
+			return nil, err
+		}
+	}
+	if !placed {
+		sr.backtrack()
 	}
 	return nil, ErrNoSolution
 }
 
 type SimpleSolver struct {}
 
-func (s *SimpleSolver) Solve(g *Game) (*Board, error) {
-	g.solveCalled = 0
-	b := g.BoardPool.Get()
+func (s *SimpleSolver) Solve(sr *Search, g *Game) (*Board, error) {
+	b := g.NewBoard()
 	defer g.BoardPool.Put(b)
 
-	return s.solveBoard(g, b, g.Cols)
+	sr.setPhase("search")
+	return s.solveBoard(sr, g, b, g.Queens(), 0, 0)
 }	
 
-func (s *SimpleSolver) solveBoard(g *Game, b *Board, n int) (*Board, error) {
+// solveBoard only places queens at or after row, col. The order in which
+// queens are placed does not matter, so earlier fields need no retry.
+func (s *SimpleSolver) solveBoard(sr *Search, g *Game, b *Board, n, row, col int) (*Board, error) {
 	if n == 0 {
 		return b, nil
 	}
-	g.solveCalled++
+	if err := sr.enter(g.Queens() - n); err != nil {
+		return nil, err
+	}
 
-	for row, col, err := b.FindEmpty(0, 0); err == nil; row, col, err = b.FindNextEmpty(row, col) {
+	placed := false
+	for row, col, err := b.FindEmpty(row, col); err == nil; row, col, err = b.FindNextEmpty(row, col) {
 		// Create a new board
 		nb := g.BoardPool.Get()
 		nb.CopyFrom(b)
 		// Place the queen on the empty place, return on error
+		sr.place()
+		placed = true
 		if err := g.PlaceQueen(nb, row, col); err != nil {
 			return nil, err
 		}
 		// Try to solve this board
-		res, err := g.solveBoard(nb, n-1)
+		res, err := s.solveBoard(sr, g, nb, n-1, row, col)
 		if err == nil {
 			return res, nil
 		}
 		// No solution found, return the board to the pool
 		g.BoardPool.pool.Put(nb)
+		if !errors.Is(err, ErrNoSolution) {
+			return nil, err
+		}
+	}
+	if !placed {
+		sr.backtrack()
 	}
 	return nil, ErrNoSolution
 }
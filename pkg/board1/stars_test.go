@@ -0,0 +1,143 @@
+package board1
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"testing"
+)
+
+// loadPuzzle loads a puzzle in JSON or in the text format.
+func loadPuzzle(t testing.TB, name string) *Puzzle {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	p, err := LoadPuzzle(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// checkStars fails if b is not a solution of g: every row, column and
+// area must hold g.Stars queens, and no queens may touch.
+func checkStars(t *testing.T, g *Game, b *Board) {
+	t.Helper()
+	k := g.stars()
+	for i := range g.Rows {
+		if n := b.queensInRow(i); n != k {
+			t.Errorf("row %d has %d queens, want %d", i+1, n, k)
+		}
+	}
+	for i := range g.Cols {
+		if n := b.queensInColumn(i); n != k {
+			t.Errorf("column %d has %d queens, want %d", i+1, n, k)
+		}
+	}
+	for i, a := range g.Areas {
+		if n := b.queensIn(a.Cells); n != k {
+			t.Errorf("%s has %d queens, want %d", areaName(g.Areas, i), n, k)
+		}
+	}
+	for row := range b.Rows {
+		for col := range b.Cols {
+			if b.Get(row, col) != Queen {
+				continue
+			}
+			for _, d := range []Position{{0, 1}, {1, -1}, {1, 0}, {1, 1}} {
+				r, c := row+d[0], col+d[1]
+				if r < b.Rows && c >= 0 && c < b.Cols && b.Get(r, c) == Queen {
+					t.Errorf("queens on (%d, %d) and (%d, %d) touch", row+1, col+1, r+1, c+1)
+				}
+			}
+		}
+	}
+}
+
+func TestStarsSolvers(t *testing.T) {
+	p := loadPuzzle(t, "testdata/star-battle.json")
+	if p.Stars != 2 {
+		t.Fatalf("got %d stars, want 2", p.Stars)
+	}
+	// SimpleSolver tries the fields one by one, too slow for this board.
+	solvers := []Solver{&AreaSolver{}, &DLXSolver{}, &BitSolver{}, &PropagationSolver{}, &ParallelSolver{}}
+	for _, s := range solvers {
+		g := p.Game()
+		b, _, err := g.Solve(s)
+		if err != nil {
+			t.Fatalf("%T: %v", s, err)
+		}
+		checkStars(t, g, b)
+		var got Puzzle
+		got.SetSolution(b)
+		if !slices.EqualFunc(got.Solution, p.Solution, slices.Equal) {
+			t.Errorf("%T: got solution %v, want %v", s, got.Solution, p.Solution)
+		}
+	}
+}
+
+func TestStarsCountSolutions(t *testing.T) {
+	// Two queens per row and column that do not touch need a board of
+	// size 8, which has 2 solutions.
+	want := map[int]int{7: 0, 8: 2}
+	unique := loadPuzzle(t, "testdata/star-battle.json").Game()
+
+	for _, e := range []Enumerator{&AreaSolver{}, &DLXSolver{}, &BitSolver{}, &ParallelSolver{}} {
+		for n, w := range want {
+			g := rowAreas(n)
+			g.Stars = 2
+			var got int
+			for b, err := range g.Solutions(e) {
+				if err != nil {
+					t.Fatalf("%T: %v", e, err)
+				}
+				checkStars(t, g, b)
+				got++
+			}
+			if got != w {
+				t.Errorf("%T: size %d: got %d solutions, want %d", e, n, got, w)
+			}
+		}
+		if got, err := unique.CountSolutions(e, 0); err != nil || got != 1 {
+			t.Errorf("%T: got %d solutions, %v, want 1", e, got, err)
+		}
+	}
+}
+
+func TestStarsPlaceQueen(t *testing.T) {
+	g := rowAreas(8)
+	g.Stars = 2
+	b := &Board{Fields: make([]State, 64), Rows: 8, Cols: 8}
+	if err := g.PlaceQueen(b, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	// One queen only blocks its neighbours.
+	if b.Get(0, 1) != Blocked || b.Get(1, 1) != Blocked || b.Get(0, 2) != Empty || b.Get(2, 0) != Empty {
+		t.Fatalf("got board %v", b.Fields)
+	}
+	// The second queen fills the row.
+	if err := g.PlaceQueen(b, 0, 5); err != nil {
+		t.Fatal(err)
+	}
+	if b.Get(0, 2) != Blocked || b.Get(2, 0) != Empty || b.Get(2, 5) != Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+}
+
+func TestStarsJSON(t *testing.T) {
+	p := loadPuzzle(t, "testdata/star-battle.json")
+	var buf bytes.Buffer
+	if err := EncodePuzzle(&buf, NewPuzzle(p.Game())); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodePuzzle(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stars != 2 || got.Game().Stars != 2 {
+		t.Errorf("got %d stars after a round trip", got.Stars)
+	}
+}
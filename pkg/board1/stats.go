@@ -0,0 +1,210 @@
+package board1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SolveStats are the counts of one search.
+type SolveStats struct {
+	// Nodes is the number of search nodes visited.
+	Nodes int64 `json:"nodes"`
+	// Placements is the number of queens placed by the search.
+	Placements int64 `json:"placements"`
+	// Backtracks is the number of dead ends: nodes where no queen
+	// could be placed to continue.
+	Backtracks int64 `json:"backtracks"`
+	// Branches is the number of guesses of solvers that only search
+	// when their deductions get stuck, like the PropagationSolver.
+	Branches int64 `json:"branches"`
+	// MaxDepth is the depth of the deepest search node, the number of
+	// queens the search placed to get there.
+	MaxDepth int `json:"maxDepth"`
+	// Boards is the number of boards the BoardPool allocated.
+	Boards int `json:"boards"`
+	// Elapsed is the time of the whole search.
+	Elapsed time.Duration `json:"elapsedNs"`
+	// Phases holds the time of the phases of the solver, in order.
+	Phases []Phase `json:"phases,omitempty"`
+}
+
+// Phase is a part of a search, like building the masks of the BitSolver.
+type Phase struct {
+	Name    string        `json:"name"`
+	Elapsed time.Duration `json:"elapsedNs"`
+}
+
+func (s SolveStats) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "nodes: %d\n", s.Nodes)
+	fmt.Fprintf(&sb, "placements: %d\n", s.Placements)
+	fmt.Fprintf(&sb, "backtracks: %d\n", s.Backtracks)
+	fmt.Fprintf(&sb, "branches: %d\n", s.Branches)
+	fmt.Fprintf(&sb, "max depth: %d\n", s.MaxDepth)
+	fmt.Fprintf(&sb, "boards: %d\n", s.Boards)
+	fmt.Fprintf(&sb, "elapsed: %v\n", s.Elapsed)
+	for _, p := range s.Phases {
+		fmt.Fprintf(&sb, "phase %s: %v\n", p.Name, p.Elapsed)
+	}
+	return sb.String()
+}
+
+// Search is one run of a solver. It holds the limits of the run and
+// counts the work of the solver. Game.Solve and the other methods of Game
+// create one for every search, NewSearch is only needed to call a Solver
+// directly.
+type Search struct {
+	ctx    context.Context
+	budget Budget
+	// counts are shared with the searches of withContext.
+	*counts
+}
+
+type counts struct {
+	nodes      atomic.Int64
+	placements atomic.Int64
+	backtracks atomic.Int64
+	branches   atomic.Int64
+	maxDepth   atomic.Int64
+
+	start time.Time
+	// pool and boards are set by bind, boards holds the boards
+	// of the pool when the search started.
+	pool   *BoardPool
+	boards int
+
+	mu         sync.Mutex
+	phases     []Phase
+	phase      string
+	phaseStart time.Time
+	elapsed    time.Duration
+	done       bool
+}
+
+// NewSearch returns a search that stops when ctx is done or budget
+// is used up.
+func NewSearch(ctx context.Context, budget Budget) *Search {
+	now := time.Now()
+	return &Search{
+		ctx:    ctx,
+		budget: budget,
+		counts: &counts{start: now, phaseStart: now},
+	}
+}
+
+// bind counts the boards allocated by the pool of g.
+func (s *Search) bind(g *Game) *Search {
+	s.pool = g.BoardPool
+	s.boards = g.BoardPool.MaxEntries()
+	return s
+}
+
+// withContext returns a search with the same counts that stops when
+// ctx is done.
+func (s *Search) withContext(ctx context.Context) *Search {
+	return &Search{ctx: ctx, budget: s.budget, counts: s.counts}
+}
+
+// checkEvery is the number of nodes between two checks of the context.
+const checkEvery = 256
+
+// enter counts a node at depth, the number of queens placed by the search
+// on the board of the node. It returns a *StoppedError when the search
+// must stop.
+func (s *Search) enter(depth int) error {
+	n := s.nodes.Add(1)
+	for {
+		d := s.maxDepth.Load()
+		if int64(depth) <= d || s.maxDepth.CompareAndSwap(d, int64(depth)) {
+			break
+		}
+	}
+	if s.budget.Nodes > 0 && n > int64(s.budget.Nodes) ||
+		s.budget.Placements > 0 && s.placements.Load() > s.budget.Placements {
+		return s.stopped(ErrBudget)
+	}
+	if n%checkEvery == 0 {
+		if err := s.ctx.Err(); err != nil {
+			return s.stopped(err)
+		}
+	}
+	return nil
+}
+
+// place counts a queen placed by the search.
+func (s *Search) place() {
+	s.placements.Add(1)
+}
+
+// backtrack counts a dead end.
+func (s *Search) backtrack() {
+	s.backtracks.Add(1)
+}
+
+// branch counts a guess.
+func (s *Search) branch() {
+	s.branches.Add(1)
+}
+
+// setPhase ends the current phase and starts phase name.
+func (s *Search) setPhase(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endPhase(time.Now())
+	s.phase = name
+}
+
+// endPhase records the current phase. The caller holds mu.
+func (s *Search) endPhase(now time.Time) {
+	if s.phase != "" {
+		s.phases = append(s.phases, Phase{Name: s.phase, Elapsed: now.Sub(s.phaseStart)})
+	}
+	s.phase = ""
+	s.phaseStart = now
+}
+
+// finish ends the search, later work is not timed.
+func (s *Search) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
+	now := time.Now()
+	s.endPhase(now)
+	s.elapsed = now.Sub(s.start)
+	s.done = true
+}
+
+// Stats returns the counts of the search so far.
+func (s *Search) Stats() SolveStats {
+	st := SolveStats{
+		Nodes:      s.nodes.Load(),
+		Placements: s.placements.Load(),
+		Backtracks: s.backtracks.Load(),
+		Branches:   s.branches.Load(),
+		MaxDepth:   int(s.maxDepth.Load()),
+	}
+	if s.pool != nil {
+		st.Boards = s.pool.MaxEntries() - s.boards
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.Elapsed = s.elapsed
+	if !s.done {
+		st.Elapsed = time.Since(s.start)
+	}
+	st.Phases = append([]Phase(nil), s.phases...)
+	if s.phase != "" {
+		st.Phases = append(st.Phases, Phase{Name: s.phase, Elapsed: time.Since(s.phaseStart)})
+	}
+	return st
+}
+
+func (s *Search) stopped(err error) error {
+	return &StoppedError{Err: err, Stats: s.Stats()}
+}
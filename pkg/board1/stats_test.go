@@ -0,0 +1,95 @@
+package board1
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSolveStats(t *testing.T) {
+	solvers := []Solver{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &ParallelSolver{}}
+	for _, s := range solvers {
+		g := rowAreas(6)
+		_, stats, err := g.Solve(s)
+		if err != nil {
+			t.Fatalf("%T: %v", s, err)
+		}
+		if stats.Nodes == 0 || stats.Placements == 0 {
+			t.Errorf("%T: got %d nodes and %d placements", s, stats.Nodes, stats.Placements)
+		}
+		// The last queen is placed below the deepest node.
+		if stats.MaxDepth != 5 {
+			t.Errorf("%T: got max depth %d, want 5", s, stats.MaxDepth)
+		}
+		if stats.Elapsed <= 0 {
+			t.Errorf("%T: got elapsed %v", s, stats.Elapsed)
+		}
+	}
+}
+
+func TestSolveStatsBacktracks(t *testing.T) {
+	solvers := []Solver{&SimpleSolver{}, &AreaSolver{}, &DLXSolver{}, &BitSolver{}, &PropagationSolver{}}
+	for _, s := range solvers {
+		g := rowAreas(3)
+		_, stats, err := g.Solve(s)
+		if err != ErrNoSolution {
+			t.Fatalf("%T: got error %v, want %v", s, err, ErrNoSolution)
+		}
+		if stats.Backtracks == 0 {
+			t.Errorf("%T: got no backtracks", s)
+		}
+	}
+}
+
+func TestSolveStatsBoards(t *testing.T) {
+	g := rowAreas(6)
+	_, stats, err := g.Solve(&AreaSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Boards == 0 || stats.Boards > g.BoardPool.MaxEntries() {
+		t.Errorf("got %d boards allocated, pool has %d", stats.Boards, g.BoardPool.MaxEntries())
+	}
+}
+
+func TestSolveStatsPhases(t *testing.T) {
+	g := rowAreas(6)
+	_, stats, err := g.Solve(&BitSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range stats.Phases {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "prepare,search" {
+		t.Errorf("got phases %s, want prepare,search", got)
+	}
+}
+
+func TestSolveStatsJSON(t *testing.T) {
+	g := rowAreas(6)
+	_, stats, err := g.Solve(&AreaSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got SolveStats
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Nodes != stats.Nodes || got.Elapsed != stats.Elapsed || len(got.Phases) != len(stats.Phases) {
+		t.Errorf("got %s, want %+v", data, stats)
+	}
+	for _, key := range []string{`"nodes"`, `"placements"`, `"backtracks"`, `"maxDepth"`, `"boards"`, `"elapsedNs"`, `"phases"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("%s has no %s", data, key)
+		}
+	}
+	if !strings.Contains(stats.String(), "phase search: ") {
+		t.Errorf("got text %q", stats)
+	}
+}
@@ -0,0 +1,39 @@
+package board1
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotUnique = errors.New("more than one solution found")
+
+// UniqueResult is the result of CheckUnique.
+type UniqueResult struct {
+	// Unique is true if the game has exactly one solution.
+	Unique bool
+	// Solutions holds the solution of a unique game. For an ambiguous game
+	// it holds the first two solutions found, as a witness.
+	Solutions []*Board
+}
+
+// CheckUnique checks whether g has exactly one solution.
+// The search stops as soon as a second solution is found.
+// ErrNoSolution is returned if g has no solution at all.
+func CheckUnique(g *Game) (UniqueResult, error) {
+	var res UniqueResult
+	s := &AreaSolver{}
+	err := s.Enumerate(NewSearch(context.Background(), Budget{}).bind(g), g, func(b *Board) bool {
+		res.Solutions = append(res.Solutions, b)
+		return len(res.Solutions) < 2
+	})
+	if err != nil {
+		return res, err
+	}
+	switch len(res.Solutions) {
+	case 0:
+		return res, ErrNoSolution
+	case 1:
+		res.Unique = true
+	}
+	return res, nil
+}
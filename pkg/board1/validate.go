@@ -0,0 +1,194 @@
+package board1
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxBoardSize is the largest number of rows or columns of a valid board.
+const MaxBoardSize = 1000
+
+// Issue is a problem in a puzzle. Line and Col point to the field in the
+// puzzle file, counting from 1. They are 0 for issues of the whole puzzle.
+type Issue struct {
+	Line int    `json:"line,omitempty"`
+	Col  int    `json:"col,omitempty"`
+	Msg  string `json:"msg"`
+}
+
+func (i Issue) Error() string {
+	if i.Line == 0 {
+		return i.Msg
+	}
+	return fmt.Sprintf("line %d, column %d: %s", i.Line, i.Col, i.Msg)
+}
+
+// ValidationError holds all issues found in a puzzle.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, is := range e.Issues {
+		msgs[i] = is.Error()
+	}
+	return "invalid puzzle: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Issues))
+	for i, is := range e.Issues {
+		errs[i] = is
+	}
+	return errs
+}
+
+// issues returns a ValidationError for issues, nil if there are none.
+func issues(is []Issue) error {
+	if len(is) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(is, func(a, b Issue) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Col, b.Col))
+	})
+	return &ValidationError{Issues: is}
+}
+
+// Validate checks that g is a proper puzzle: a board of at most
+// MaxBoardSize rows and columns, as many areas as the rows or columns with
+// fields, whichever are fewer, and non-empty, connected areas that cover
+// every field that is no hole once. A board without areas, like the chess
+// N-queens puzzle, is valid too.
+// Issues are reported at row+1, col+1. The error is a *ValidationError.
+func Validate(g *Game) error {
+	at := func(p Position) (int, int) {
+		return p[0] + 1, p[1] + 1
+	}
+	return issues(validateAreas(g.Rows, g.Cols, g.Areas, g.Holes, at))
+}
+
+// validateAreas validates areas and holes on a board of rows x cols.
+// at returns the line and column of a field in the puzzle file.
+func validateAreas(rows, cols int, areas []Area, holes []Position, at func(Position) (int, int)) []Issue {
+	var is []Issue
+	add := func(p Position, format string, args ...any) {
+		var line, col int
+		if p != nil {
+			line, col = at(p)
+		}
+		is = append(is, Issue{Line: line, Col: col, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	// Check the size before it is used to allocate.
+	switch {
+	case rows < 0 || cols < 0:
+		add(nil, "board of %dx%d has a negative size", rows, cols)
+		return is
+	case rows == 0 || cols == 0:
+		add(nil, "board has no fields")
+		return is
+	case rows > MaxBoardSize || cols > MaxBoardSize:
+		add(nil, "board of %dx%d is larger than %dx%d", rows, cols, MaxBoardSize, MaxBoardSize)
+		return is
+	}
+	// owner holds the area of every field, -1 if none and -2 for a hole.
+	owner := make([]int, rows*cols)
+	for i := range owner {
+		owner[i] = -1
+	}
+	for _, h := range holes {
+		if h[0] < 0 || h[0] >= rows || h[1] < 0 || h[1] >= cols {
+			is = append(is, Issue{Msg: fmt.Sprintf("hole %s is outside the board", FieldName(h))})
+			continue
+		}
+		owner[h[0]*cols+h[1]] = -2
+	}
+	if len(areas) == 0 {
+		return is
+	}
+	g := Game{Rows: rows, Cols: cols, Holes: holes}
+	switch r, c := g.lines(); {
+	case r <= c && len(areas) != r:
+		add(nil, "board has %d rows but %d areas", r, len(areas))
+	case c < r && len(areas) != c:
+		add(nil, "board has %d columns but %d areas", c, len(areas))
+	}
+	name := func(a int) string {
+		return areaName(areas, a)
+	}
+
+	for a, area := range areas {
+		if len(area.Cells) == 0 {
+			add(nil, "%s is empty", name(a))
+			continue
+		}
+		for _, p := range area.Cells {
+			if p[0] < 0 || p[0] >= rows || p[1] < 0 || p[1] >= cols {
+				is = append(is, Issue{Msg: fmt.Sprintf("field (%d, %d) of %s is outside the board", p[0]+1, p[1]+1, name(a))})
+				continue
+			}
+			f := p[0]*cols + p[1]
+			switch {
+			case owner[f] == -2:
+				add(p, "field is a hole and in %s", name(a))
+				continue
+			case owner[f] >= 0:
+				add(p, "field is in %s and %s", name(owner[f]), name(a))
+				continue
+			}
+			owner[f] = a
+		}
+	}
+	for f, a := range owner {
+		if a == -1 {
+			add(Position{f / cols, f % cols}, "field is in no area")
+		}
+	}
+
+	for a, area := range areas {
+		parts := components(area.Cells)
+		for _, part := range parts[min(1, len(parts)):] {
+			add(part[0], "%s is not connected, this part is separated", name(a))
+		}
+	}
+	return is
+}
+
+// components splits cells in their orthogonally connected parts.
+// The part with the first cell comes first.
+func components(cells []Position) [][]Position {
+	index := make(map[[2]int]int, len(cells))
+	for i, p := range cells {
+		index[[2]int{p[0], p[1]}] = i
+	}
+	seen := make([]bool, len(cells))
+	var parts [][]Position
+	for i := range cells {
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		part := []Position{cells[i]}
+		for k := 0; k < len(part); k++ {
+			for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				j, ok := index[[2]int{part[k][0] + d[0], part[k][1] + d[1]}]
+				if ok && !seen[j] {
+					seen[j] = true
+					part = append(part, cells[j])
+				}
+			}
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,145 @@
+package board1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadAreasValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		board  string
+		issues []Issue
+	}{
+		{
+			name:  "valid",
+			board: "0 0 1\n2 1 1\n2 2 1\n",
+		},
+		{
+			name:  "ragged row",
+			board: "0 0 1\n2 1\n2 2 1\n",
+			issues: []Issue{
+				{Line: 2, Col: 3, Msg: "row has 2 fields, the first row has 3"},
+			},
+		},
+		{
+			name:  "rectangular",
+			board: "0 0 1\n0 1 1\n",
+		},
+		{
+			name:  "more areas than rows",
+			board: "0 0 1\n2 2 1\n",
+			issues: []Issue{
+				{Msg: "board has 2 rows but 3 areas"},
+			},
+		},
+		{
+			name:  "holes",
+			board: "0 0 .\n1 1 2\n. 2 2\n",
+		},
+		{
+			name:  "more areas than columns",
+			board: "0 .\n1 .\n2 2\n",
+			issues: []Issue{
+				{Msg: "board has 2 columns but 3 areas"},
+			},
+		},
+		{
+			name:  "not connected",
+			board: "0\t1\t0\n1\t1\t2\n2\t2\t2\n",
+			issues: []Issue{
+				{Line: 1, Col: 5, Msg: "area 0 is not connected, this part is separated"},
+			},
+		},
+		{
+			name:  "no fields",
+			board: "\n\n",
+			issues: []Issue{
+				{Msg: "board has no fields"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadGame(strings.NewReader(tt.board))
+			if tt.issues == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("got error %v, want a ValidationError", err)
+			}
+			if len(ve.Issues) != len(tt.issues) {
+				t.Fatalf("got issues %v, want %v", ve.Issues, tt.issues)
+			}
+			for i := range tt.issues {
+				if ve.Issues[i] != tt.issues[i] {
+					t.Errorf("got issue %v, want %v", ve.Issues[i], tt.issues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(rowAreas(5)); err != nil {
+		t.Error(err)
+	}
+
+	g := NewGame(2, 2,
+		NewArea("", Position{0, 0}, Position{1, 1}),
+		NewArea("", Position{0, 1}, Position{0, 0}),
+	)
+	err := Validate(g)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("got error %v, want a ValidationError", err)
+	}
+	want := []Issue{
+		{Msg: "area 1 is not connected, this part is separated", Line: 2, Col: 2},
+		{Msg: "field is in area 1 and area 2", Line: 1, Col: 1},
+		{Msg: "field is in no area", Line: 2, Col: 1},
+	}
+	for _, w := range want {
+		found := false
+		for _, is := range ve.Issues {
+			found = found || is == w
+		}
+		if !found {
+			t.Errorf("issue %v not found in %v", w, ve.Issues)
+		}
+	}
+	// Every issue can be checked on its own.
+	if !errors.Is(err, want[2]) {
+		t.Error("errors.Is does not find the issue")
+	}
+}
+
+func TestComponentsLarge(t *testing.T) {
+	// A single area of 300x300 fields.
+	var cells []Position
+	for row := range 300 {
+		for col := range 300 {
+			cells = append(cells, Position{row, col})
+		}
+	}
+	parts := components(cells)
+	if len(parts) != 1 || len(parts[0]) != len(cells) {
+		t.Fatalf("got %d parts, want 1 of %d fields", len(parts), len(cells))
+	}
+
+	// A row with a gap is two parts.
+	cells = cells[:0]
+	for col := range 300 {
+		if col != 150 {
+			cells = append(cells, Position{0, col})
+		}
+	}
+	if parts := components(cells); len(parts) != 2 || len(parts[0]) != 150 || len(parts[1]) != 149 {
+		t.Errorf("got %d parts, want parts of 150 and 149 fields", len(parts))
+	}
+}
@@ -0,0 +1,193 @@
+// Package generate creates Queens puzzles with exactly one solution.
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+var ErrGiveUp = errors.New("no puzzle with a unique solution found")
+
+// Puzzle is a generated puzzle with its solution.
+type Puzzle struct {
+	Size   int
+	Areas  []board1.Area
+	Queens []board1.Position
+	// Rating is set by GenerateTier.
+	Rating board1.Rating
+}
+
+// Game returns a new game for the puzzle.
+func (p *Puzzle) Game() *board1.Game {
+	// Solvers sort the areas of a game, keep ours in order.
+	return board1.NewGame(p.Size, p.Size, slices.Clone(p.Areas)...)
+}
+
+// Write writes the puzzle in the format read by board1.LoadAreas.
+func (p *Puzzle) Write(w io.Writer) error {
+	return board1.WriteAreas(w, p.Size, p.Size, p.Areas)
+}
+
+// Generator creates puzzles of one size.
+type Generator struct {
+	Size int
+	// Attempts is the number of layouts tried before giving up.
+	Attempts int
+	// Repairs is the number of border moves tried on one layout.
+	Repairs int
+	// Mutations is the number of border moves GenerateTier tries
+	// on one layout to reach the difficulty.
+	Mutations int
+
+	rnd *rand.Rand
+}
+
+// New returns a generator for puzzles of size x size.
+// Generators with the same seed create the same puzzles.
+func New(size int, seed uint64) *Generator {
+	return &Generator{
+		Size:      size,
+		Attempts:  100,
+		Repairs:   10 * size * size,
+		Mutations: 20 * size,
+		rnd:       rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+	}
+}
+
+// Generate picks a random placement of the queens, grows an area around
+// every queen and moves fields between areas until the placement is the
+// only solution.
+func (g *Generator) Generate() (*Puzzle, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	for range g.Attempts {
+		queens := g.queens()
+		l := newLayout(g.Size)
+		l.grow(g.rnd, queens)
+		if g.repair(l, queens) {
+			return g.puzzle(l, queens), nil
+		}
+	}
+	return nil, ErrGiveUp
+}
+
+// check returns an error if no puzzle of the size exists, or if it is
+// too large for the BitSolver that checks the solutions.
+func (g *Generator) check() error {
+	if g.Size < 1 || g.Size == 2 || g.Size == 3 {
+		return fmt.Errorf("no puzzle of size %d exists", g.Size)
+	}
+	if g.Size*g.Size > board1.MaxBitFields {
+		return fmt.Errorf("board of %dx%d has more than %d fields", g.Size, g.Size, board1.MaxBitFields)
+	}
+	return nil
+}
+
+func (g *Generator) puzzle(l *layout, queens []int) *Puzzle {
+	p := &Puzzle{
+		Size:  g.Size,
+		Areas: l.areas(len(queens)),
+	}
+	for _, q := range queens {
+		p.Queens = append(p.Queens, board1.Position{q / g.Size, q % g.Size})
+	}
+	return p
+}
+
+// queens returns the fields of a random valid placement, one per row.
+func (g *Generator) queens() []int {
+	cols := make([]int, 0, g.Size)
+	var place func() bool
+	place = func() bool {
+		row := len(cols)
+		if row == g.Size {
+			return true
+		}
+		for _, c := range g.rnd.Perm(g.Size) {
+			if slices.Contains(cols, c) || (row > 0 && abs(cols[row-1]-c) < 2) {
+				continue
+			}
+			cols = append(cols, c)
+			if place() {
+				return true
+			}
+			cols = cols[:row]
+		}
+		return false
+	}
+	place()
+
+	res := make([]int, len(cols))
+	for row, col := range cols {
+		res[row] = row*g.Size + col
+	}
+	return res
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// solutions returns up to two solutions of the layout, none if the
+// search fails.
+func (g *Generator) solutions(l *layout, queens []int) []*board1.Board {
+	var res []*board1.Board
+	for b, err := range g.puzzle(l, queens).Game().Solutions(&board1.BitSolver{}) {
+		if err != nil {
+			return nil
+		}
+		res = append(res, b)
+		if len(res) == 2 {
+			break
+		}
+	}
+	return res
+}
+
+// repair moves fields between areas until the queens are the only
+// solution. Every move takes a field of a queen of another solution, that
+// is not one of our queens, to a neighbouring area. That solution now has
+// two queens in one area and none in another, while ours stays valid.
+func (g *Generator) repair(l *layout, queens []int) bool {
+	for range g.Repairs {
+		solutions := g.solutions(l, queens)
+		if len(solutions) == 1 {
+			return true
+		}
+
+		var other []int
+		for _, b := range solutions {
+			for i, s := range b.Fields {
+				if s == board1.Queen && !slices.Contains(queens, i) {
+					other = append(other, i)
+				}
+			}
+		}
+		if !g.move(l, other) {
+			return false
+		}
+	}
+	return false
+}
+
+// move moves one of fields to a neighbouring area,
+// it returns false if none of them can move.
+func (g *Generator) move(l *layout, fields []int) bool {
+	for _, i := range g.rnd.Perm(len(fields)) {
+		f := fields[i]
+		if to := l.movable(f); len(to) > 0 {
+			l.cells[f] = to[g.rnd.IntN(len(to))]
+			return true
+		}
+	}
+	return false
+}
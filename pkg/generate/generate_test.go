@@ -0,0 +1,93 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+func TestGenerate(t *testing.T) {
+	for size := 4; size <= 10; size++ {
+		p, err := New(size, uint64(size)).Generate()
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+
+		// The written puzzle loads and has our queens as only solution.
+		var buf bytes.Buffer
+		if err := p.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		a, n, err := board1.LoadAreas(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != size || len(a) != size {
+			t.Fatalf("size %d: loaded %d rows and %d areas", size, n, len(a))
+		}
+		res, err := board1.CheckUnique(board1.NewGame(n, n, a...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Unique {
+			t.Fatalf("size %d: puzzle is not unique", size)
+		}
+		for _, q := range p.Queens {
+			if res.Solutions[0].Get(q[0], q[1]) != board1.Queen {
+				t.Errorf("size %d: no queen at %v", size, q)
+			}
+		}
+	}
+}
+
+func TestGenerateConnected(t *testing.T) {
+	p, err := New(9, 42).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newLayout(p.Size)
+	for i, a := range p.Areas {
+		for _, pos := range a.Cells {
+			l.cells[pos[0]*p.Size+pos[1]] = i
+		}
+	}
+	for i := range p.Areas {
+		if !l.connectedWithout(i, -1) {
+			t.Errorf("area %d is not connected", i)
+		}
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	var a, b bytes.Buffer
+	p, err := New(8, 7).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Write(&a)
+	p, err = New(8, 7).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Write(&b)
+	if a.String() != b.String() {
+		t.Error("same seed gave different puzzles")
+	}
+}
+
+func TestGenerateImpossible(t *testing.T) {
+	if _, err := New(3, 1).Generate(); err == nil {
+		t.Error("expected an error for size 3")
+	}
+	for _, size := range []int{17, 100} {
+		_, err := New(size, 1).Generate()
+		if err == nil || errors.Is(err, ErrGiveUp) {
+			t.Errorf("size %d: got error %v, want an error for the size", size, err)
+		}
+		if _, err := New(size, 1).GenerateTier(board1.Easy); err == nil || errors.Is(err, ErrGiveUp) {
+			t.Errorf("size %d: got error %v from GenerateTier, want an error for the size", size, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package generate
+
+import (
+	"math/rand/v2"
+	"slices"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// layout holds the area of every field of a square board.
+type layout struct {
+	size  int
+	cells []int
+}
+
+func newLayout(size int) *layout {
+	l := &layout{
+		size:  size,
+		cells: make([]int, size*size),
+	}
+	for i := range l.cells {
+		l.cells[i] = -1
+	}
+	return l
+}
+
+func (l *layout) clone() *layout {
+	return &layout{
+		size:  l.size,
+		cells: append([]int(nil), l.cells...),
+	}
+}
+
+// neighbours returns the orthogonal neighbours of field f.
+func (l *layout) neighbours(f int) []int {
+	row, col := f/l.size, f%l.size
+	res := make([]int, 0, 4)
+	if row > 0 {
+		res = append(res, f-l.size)
+	}
+	if row < l.size-1 {
+		res = append(res, f+l.size)
+	}
+	if col > 0 {
+		res = append(res, f-1)
+	}
+	if col < l.size-1 {
+		res = append(res, f+1)
+	}
+	return res
+}
+
+// grow assigns every field to an area by growing the areas from seeds,
+// one random neighbour at a time. Seed i starts area i.
+func (l *layout) grow(rnd *rand.Rand, seeds []int) {
+	type edge struct{ field, area int }
+	var frontier []edge
+	for a, f := range seeds {
+		l.cells[f] = a
+		for _, n := range l.neighbours(f) {
+			frontier = append(frontier, edge{n, a})
+		}
+	}
+	for len(frontier) > 0 {
+		i := rnd.IntN(len(frontier))
+		e := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		if l.cells[e.field] >= 0 {
+			continue
+		}
+		l.cells[e.field] = e.area
+		for _, n := range l.neighbours(e.field) {
+			if l.cells[n] < 0 {
+				frontier = append(frontier, edge{n, e.area})
+			}
+		}
+	}
+}
+
+// connectedWithout reports whether area a stays connected without field f.
+func (l *layout) connectedWithout(a, f int) bool {
+	start, size := -1, 0
+	for i, c := range l.cells {
+		if c == a && i != f {
+			size++
+			start = i
+		}
+	}
+	if start < 0 {
+		return false
+	}
+	seen := map[int]bool{start: true}
+	todo := []int{start}
+	for len(todo) > 0 {
+		c := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		for _, n := range l.neighbours(c) {
+			if n != f && l.cells[n] == a && !seen[n] {
+				seen[n] = true
+				todo = append(todo, n)
+			}
+		}
+	}
+	return len(seen) == size
+}
+
+// movable returns the areas field f can move to: areas of neighbours
+// other than its own, if its own area stays connected without it.
+func (l *layout) movable(f int) []int {
+	a := l.cells[f]
+	if !l.connectedWithout(a, f) {
+		return nil
+	}
+	var res []int
+	for _, n := range l.neighbours(f) {
+		if b := l.cells[n]; b != a && !slices.Contains(res, b) {
+			res = append(res, b)
+		}
+	}
+	return res
+}
+
+// areas converts the layout to areas, area i is at index i.
+func (l *layout) areas(n int) []board1.Area {
+	res := make([]board1.Area, n)
+	for a := range res {
+		res[a].Label = board1.DefaultLabel(a)
+	}
+	for i, a := range l.cells {
+		res[a].Cells = append(res[a].Cells, board1.Position{i / l.size, i % l.size})
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package generate
+
+import (
+	"slices"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// tierTargets are the scores GenerateTier aims for, in the middle of
+// the score range of each tier of board1.Rate.
+var tierTargets = map[board1.Tier]int{
+	board1.Easy:     12,
+	board1.Medium:   37,
+	board1.Hard:     62,
+	board1.Expert:   87,
+	board1.Guessing: 125,
+}
+
+// GenerateTier creates a puzzle of the given difficulty tier.
+//
+// It starts from a unique puzzle and keeps moving fields between
+// neighbouring areas, keeping the areas connected. A move is kept when
+// the puzzle stays unique and its score does not move away from the
+// target score of the tier.
+func (g *Generator) GenerateTier(tier board1.Tier) (*Puzzle, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	target := tierTargets[tier]
+
+	for range g.Attempts {
+		queens := g.queens()
+		l := newLayout(g.Size)
+		l.grow(g.rnd, queens)
+		if !g.repair(l, queens) {
+			continue
+		}
+		r := board1.Rate(g.puzzle(l, queens).Game())
+
+		for range g.Mutations {
+			if r.Tier == tier {
+				break
+			}
+			nl := l.clone()
+			if !g.move(nl, g.free(nl, queens)) || !g.repair(nl, queens) {
+				continue
+			}
+			nr := board1.Rate(g.puzzle(nl, queens).Game())
+			if abs(nr.Score-target) <= abs(r.Score-target) {
+				l, r = nl, nr
+			}
+		}
+		if r.Tier == tier {
+			p := g.puzzle(l, queens)
+			p.Rating = r
+			return p, nil
+		}
+	}
+	return nil, ErrGiveUp
+}
+
+// free returns the fields without a queen.
+func (g *Generator) free(l *layout, queens []int) []int {
+	res := make([]int, 0, len(l.cells))
+	for f := range l.cells {
+		if !slices.Contains(queens, f) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+func TestGenerateTier(t *testing.T) {
+	for tier := board1.Easy; tier <= board1.Guessing; tier++ {
+		t.Run(tier.String(), func(t *testing.T) {
+			p, err := New(8, 3).GenerateTier(tier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := board1.CheckUnique(p.Game())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Unique {
+				t.Fatal("puzzle is not unique")
+			}
+			r := board1.Rate(p.Game())
+			if r.Tier != tier {
+				t.Errorf("got tier %s, want %s", r.Tier, tier)
+			}
+		})
+	}
+}
+
+func TestGenerateTierLarge(t *testing.T) {
+	for _, size := range []int{9, 10} {
+		p, err := New(size, 1).GenerateTier(board1.Hard)
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if p.Size != size {
+			t.Errorf("got size %d, want %d", p.Size, size)
+		}
+		if r := board1.Rate(p.Game()); r.Tier != board1.Hard {
+			t.Errorf("size %d: got tier %s, want %s", size, r.Tier, board1.Hard)
+		}
+	}
+}
+
+func TestGenerateTierNoMutations(t *testing.T) {
+	// Without mutations a layout is only taken if it has the tier.
+	g := New(8, 1)
+	g.Mutations = 0
+	p, err := g.GenerateTier(board1.Easy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := board1.Rate(p.Game()); r.Tier != board1.Easy {
+		t.Errorf("got tier %s, want %s", r.Tier, board1.Easy)
+	}
+}
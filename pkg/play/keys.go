@@ -0,0 +1,116 @@
+package play
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Key is a key pressed by the player. Printable keys are the key itself,
+// the other keys have names like "up" and "enter".
+type Key string
+
+const (
+	KeyUp    Key = "up"
+	KeyDown  Key = "down"
+	KeyLeft  Key = "left"
+	KeyRight Key = "right"
+	KeyEnter Key = "enter"
+	KeyEsc   Key = "esc"
+	KeyCtrlC Key = "ctrl-c"
+	KeyCtrlR Key = "ctrl-r"
+)
+
+// escapes are the escape sequences of the keys, in normal and in
+// application cursor mode.
+var escapes = map[string]Key{
+	"\x1b[A": KeyUp,
+	"\x1b[B": KeyDown,
+	"\x1b[C": KeyRight,
+	"\x1b[D": KeyLeft,
+	"\x1bOA": KeyUp,
+	"\x1bOB": KeyDown,
+	"\x1bOC": KeyRight,
+	"\x1bOD": KeyLeft,
+}
+
+// ParseKeys returns the keys in b, input read from a terminal in raw mode.
+// Unknown escape sequences are skipped. An escape sequence that is cut off
+// at the end of b is returned as rest, to be parsed with the next input,
+// so a lone escape key is only seen with the key after it.
+func ParseKeys(b []byte) (keys []Key, rest []byte) {
+	s := string(b)
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			if len(s) == 1 {
+				return keys, []byte(s)
+			}
+			if s[1] == '[' || s[1] == 'O' {
+				// Find the final byte after the parameters.
+				n := 2
+				for n < len(s) && (s[n] < 0x40 || s[n] > 0x7e) {
+					n++
+				}
+				if n == len(s) {
+					return keys, []byte(s)
+				}
+				if k, ok := escapes[s[:3]]; ok && n == 2 {
+					keys = append(keys, k)
+				}
+				s = s[n+1:]
+				continue
+			}
+			keys = append(keys, KeyEsc)
+			s = s[1:]
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s)
+		s = s[n:]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, KeyEnter)
+		case 0x03:
+			keys = append(keys, KeyCtrlC)
+		case 0x12:
+			keys = append(keys, KeyCtrlR)
+		default:
+			if r >= ' ' && r != utf8.RuneError {
+				keys = append(keys, Key(r))
+			}
+		}
+	}
+	return keys, nil
+}
+
+// Help describes the keys of Handle.
+const Help = "arrows/hjkl move, space cycle, x cross, enter queen, u undo, r redo, a auto marks, c clear, q quit"
+
+// Handle changes m for key k. It returns false when the player quits.
+func (m *Model) Handle(k Key) bool {
+	switch Key(strings.ToLower(string(k))) {
+	case KeyUp, "k":
+		m.Move(-1, 0)
+	case KeyDown, "j":
+		m.Move(1, 0)
+	case KeyLeft, "h":
+		m.Move(0, -1)
+	case KeyRight, "l":
+		m.Move(0, 1)
+	case " ":
+		m.Cycle()
+	case "x":
+		m.ToggleCross()
+	case KeyEnter:
+		m.ToggleQueen()
+	case "u":
+		m.Undo()
+	case "r", KeyCtrlR:
+		m.Redo()
+	case "a":
+		m.AutoMark = !m.AutoMark
+	case "c":
+		m.Clear()
+	case "q", KeyCtrlC:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,369 @@
+// Package play holds the model of queens play, a game being played in
+// the terminal. The model keeps the marks of the player and shows the
+// fields blocked by the queens like Game.PlaceQueen blocks them.
+package play
+
+import (
+	"slices"
+	"time"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// Mark is what the player put on a field.
+type Mark int
+
+const (
+	NoMark Mark = iota
+	Cross
+	Crown
+)
+
+// Model is a game being played.
+type Model struct {
+	Game *board1.Game
+	// Row and Col are the position of the cursor.
+	Row, Col int
+	// AutoMark marks the fields blocked by the queens.
+	AutoMark bool
+
+	marks    []Mark
+	holes    []bool
+	undo     [][]Mark
+	redo     [][]Mark
+	solution *board1.Board
+
+	now     func() time.Time
+	start   time.Time
+	elapsed time.Duration
+	solved  bool
+}
+
+// New starts playing g from start, which may be nil. The queens of start
+// become crowns and its other blocked fields crosses. The solver checks
+// that g can be solved.
+func New(g *board1.Game, start *board1.Board) (*Model, error) {
+	solution, _, err := g.Solve(&board1.BitSolver{})
+	if err != nil {
+		return nil, err
+	}
+	m := &Model{
+		Game:     g,
+		AutoMark: true,
+		marks:    make([]Mark, g.Rows*g.Cols),
+		holes:    make([]bool, g.Rows*g.Cols),
+		solution: solution.Clone(),
+		now:      time.Now,
+	}
+	for _, h := range g.Holes {
+		m.holes[h[0]*g.Cols+h[1]] = true
+	}
+	if start != nil {
+		for i, s := range start.Fields {
+			if s == board1.Queen {
+				m.marks[i] = Crown
+			}
+		}
+		blocked := m.blocked()
+		for i, s := range start.Fields {
+			if s == board1.Blocked && !blocked[i] {
+				m.marks[i] = Cross
+			}
+		}
+	}
+	m.start = m.now()
+	m.check()
+	return m, nil
+}
+
+// Mark returns the mark of the player on row, col.
+func (m *Model) Mark(row, col int) Mark {
+	return m.marks[row*m.Game.Cols+col]
+}
+
+// Board returns the board as shown: the crowns of the player as queens,
+// and the crosses, with AutoMark also the fields the queens block, as
+// blocked fields.
+func (m *Model) Board() *board1.Board {
+	b := m.newBoard()
+	blocked := m.blocked()
+	for i, mk := range m.marks {
+		switch {
+		case m.holes[i]:
+		case mk == Crown:
+			b.Fields[i] = board1.Queen
+		case mk == Cross, m.AutoMark && blocked[i]:
+			b.Fields[i] = board1.Blocked
+		}
+	}
+	return b
+}
+
+// AutoMarked reports whether row, col is only blocked by a queen.
+func (m *Model) AutoMarked(row, col int) bool {
+	i := row*m.Game.Cols + col
+	return m.AutoMark && m.marks[i] == NoMark && !m.holes[i] && m.blocked()[i]
+}
+
+// Conflicts returns the crowns attacked by another crown, by the adjacency
+// rule and the diagonals of the game, and the crowns in a row, column or
+// area with more crowns than the game has stars.
+func (m *Model) Conflicts() []board1.Position {
+	var res []board1.Position
+	queens := m.queens()
+	full := m.full(queens, 1)
+	for _, q := range queens {
+		if full[q[0]*m.Game.Cols+q[1]] || m.attacked(q) {
+			res = append(res, q)
+		}
+	}
+	return res
+}
+
+// Queens returns the number of crowns.
+func (m *Model) Queens() int {
+	return len(m.queens())
+}
+
+// Solved reports whether the crowns are a solution. It is the solution of
+// the solver, or for puzzles with more than one solution another one that
+// keeps the rules.
+func (m *Model) Solved() bool {
+	return m.solved
+}
+
+// Elapsed returns the playing time, it stops when the game is solved.
+func (m *Model) Elapsed() time.Duration {
+	if m.solved {
+		return m.elapsed
+	}
+	return m.now().Sub(m.start)
+}
+
+// Move moves the cursor, it stops at the edges.
+func (m *Model) Move(dRow, dCol int) {
+	m.Row = min(max(m.Row+dRow, 0), m.Game.Rows-1)
+	m.Col = min(max(m.Col+dCol, 0), m.Game.Cols-1)
+}
+
+// Cycle changes the field at the cursor from empty to cross to crown and
+// back to empty, like a click in the web game.
+func (m *Model) Cycle() {
+	m.set((m.Mark(m.Row, m.Col) + 1) % 3)
+}
+
+// ToggleCross puts a cross on the field at the cursor or removes it.
+func (m *Model) ToggleCross() {
+	m.toggle(Cross)
+}
+
+// ToggleQueen puts a crown on the field at the cursor or removes it.
+func (m *Model) ToggleQueen() {
+	m.toggle(Crown)
+}
+
+// Clear removes all marks.
+func (m *Model) Clear() {
+	if m.solved || !slices.ContainsFunc(m.marks, func(mk Mark) bool { return mk != NoMark }) {
+		return
+	}
+	m.save()
+	clear(m.marks)
+	m.check()
+}
+
+// Undo takes back the last change, it returns false if there is none.
+func (m *Model) Undo() bool {
+	if m.solved || len(m.undo) == 0 {
+		return false
+	}
+	m.redo = append(m.redo, slices.Clone(m.marks))
+	m.marks = m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+	m.check()
+	return true
+}
+
+// Redo makes the last change taken back by Undo again.
+func (m *Model) Redo() bool {
+	if m.solved || len(m.redo) == 0 {
+		return false
+	}
+	m.undo = append(m.undo, slices.Clone(m.marks))
+	m.marks = m.redo[len(m.redo)-1]
+	m.redo = m.redo[:len(m.redo)-1]
+	m.check()
+	return true
+}
+
+func (m *Model) toggle(mk Mark) {
+	if m.Mark(m.Row, m.Col) == mk {
+		mk = NoMark
+	}
+	m.set(mk)
+}
+
+// set puts mk on the field at the cursor. A solved game and holes do not
+// change.
+func (m *Model) set(mk Mark) {
+	i := m.Row*m.Game.Cols + m.Col
+	if m.solved || m.holes[i] || m.marks[i] == mk {
+		return
+	}
+	m.save()
+	m.marks[i] = mk
+	m.check()
+}
+
+// save keeps the marks for Undo.
+func (m *Model) save() {
+	m.undo = append(m.undo, slices.Clone(m.marks))
+	m.redo = nil
+}
+
+// check stops the timer when the game is solved.
+func (m *Model) check() {
+	queens := m.queens()
+	if len(queens) != m.Game.Queens() || len(m.Conflicts()) > 0 {
+		return
+	}
+	// Crowns without conflicts can still leave an area without a queen.
+	if !m.isSolution(queens) && !m.everyArea() {
+		return
+	}
+	m.solved = true
+	m.elapsed = m.now().Sub(m.start)
+}
+
+// isSolution reports whether queens are the solution of the solver.
+func (m *Model) isSolution(queens []board1.Position) bool {
+	for _, q := range queens {
+		if m.solution.Get(q[0], q[1]) != board1.Queen {
+			return false
+		}
+	}
+	return true
+}
+
+// everyArea reports whether every area has as many crowns as the game
+// has stars.
+func (m *Model) everyArea() bool {
+	for _, a := range m.Game.Areas {
+		var n int
+		for _, p := range a.Cells {
+			if m.Mark(p[0], p[1]) == Crown {
+				n++
+			}
+		}
+		if n != m.stars() {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) queens() []board1.Position {
+	var res []board1.Position
+	for i, mk := range m.marks {
+		if mk == Crown {
+			res = append(res, board1.Position{i / m.Game.Cols, i % m.Game.Cols})
+		}
+	}
+	return res
+}
+
+// blocked returns the fields blocked by the crowns: the fields they
+// attack by the rules of the game, and the rows, columns and areas
+// holding as many crowns as the game has stars.
+func (m *Model) blocked() []bool {
+	queens := m.queens()
+	res := m.full(queens, 0)
+	for _, q := range queens {
+		for _, p := range m.around(q) {
+			res[p[0]*m.Game.Cols+p[1]] = true
+		}
+	}
+	for _, q := range queens {
+		res[q[0]*m.Game.Cols+q[1]] = false
+	}
+	return res
+}
+
+// full returns the fields of the rows, columns and areas with at least
+// stars+extra crowns.
+func (m *Model) full(queens []board1.Position, extra int) []bool {
+	g := m.Game
+	rows := make([]int, g.Rows)
+	cols := make([]int, g.Cols)
+	for _, q := range queens {
+		rows[q[0]]++
+		cols[q[1]]++
+	}
+	k := m.stars() + extra
+	res := make([]bool, len(m.marks))
+	for i := range res {
+		res[i] = rows[i/g.Cols] >= k || cols[i%g.Cols] >= k
+	}
+	for _, a := range g.Areas {
+		var n int
+		for _, p := range a.Cells {
+			if m.Mark(p[0], p[1]) == Crown {
+				n++
+			}
+		}
+		if n >= k {
+			for _, p := range a.Cells {
+				res[p[0]*g.Cols+p[1]] = true
+			}
+		}
+	}
+	return res
+}
+
+// attacked reports whether a crown is on a field q attacks.
+func (m *Model) attacked(q board1.Position) bool {
+	return slices.ContainsFunc(m.around(q), func(p board1.Position) bool {
+		return m.Mark(p[0], p[1]) == Crown
+	})
+}
+
+// around returns the fields on the board that a queen on q blocks by the
+// adjacency rule of the game, and its diagonals if the game has them.
+func (m *Model) around(q board1.Position) []board1.Position {
+	var res []board1.Position
+	on := func(row, col int) bool {
+		return row >= 0 && row < m.Game.Rows && col >= 0 && col < m.Game.Cols
+	}
+	for _, o := range m.Game.Adjacency.Offsets() {
+		if row, col := q[0]+o[0], q[1]+o[1]; on(row, col) {
+			res = append(res, board1.Position{row, col})
+		}
+	}
+	if m.Game.Diagonals {
+		for _, d := range []board1.Position{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+			for row, col := q[0]+d[0], q[1]+d[1]; on(row, col); row, col = row+d[0], col+d[1] {
+				res = append(res, board1.Position{row, col})
+			}
+		}
+	}
+	return res
+}
+
+// stars returns the number of queens per row, column and area.
+func (m *Model) stars() int {
+	return max(m.Game.Stars, 1)
+}
+
+func (m *Model) newBoard() *board1.Board {
+	b := &board1.Board{
+		Fields: make([]board1.State, m.Game.Rows*m.Game.Cols),
+		Rows:   m.Game.Rows,
+		Cols:   m.Game.Cols,
+	}
+	for i, h := range m.holes {
+		if h {
+			b.Fields[i] = board1.Hole
+		}
+	}
+	return b
+}
@@ -0,0 +1,336 @@
+package play
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// rowAreas returns a game of size n with a row per area. Size 4 has the
+// solutions 1302 and 2031, the columns of the queens per row.
+func rowAreas(n int) *board1.Game {
+	areas := make([]board1.Area, n)
+	for i := range n {
+		for j := range n {
+			areas[i].Cells = append(areas[i].Cells, board1.Position{i, j})
+		}
+	}
+	return board1.NewGame(n, n, areas...)
+}
+
+func newModel(t *testing.T, n int) (*Model, *time.Time) {
+	t.Helper()
+	m, err := New(rowAreas(n), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 4, 24, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	m.start = now
+	return m, &now
+}
+
+// play moves to every position and presses key there.
+func play(m *Model, key Key, ps ...board1.Position) {
+	for _, p := range ps {
+		m.Row, m.Col = p[0], p[1]
+		m.Handle(key)
+	}
+}
+
+func TestMove(t *testing.T) {
+	m, _ := newModel(t, 4)
+	for _, k := range []Key{KeyUp, KeyLeft, KeyDown, "l", "l", "l", "l", "j"} {
+		m.Handle(k)
+	}
+	if m.Row != 2 || m.Col != 3 {
+		t.Errorf("cursor at (%d, %d), want (2, 3)", m.Row, m.Col)
+	}
+}
+
+func TestAutoMark(t *testing.T) {
+	m, _ := newModel(t, 4)
+	play(m, KeyEnter, board1.Position{0, 1})
+	b := m.Board()
+	if b.Get(0, 1) != board1.Queen || b.Get(1, 2) != board1.Blocked || b.Get(3, 1) != board1.Blocked || b.Get(2, 3) != board1.Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+	if !m.AutoMarked(1, 2) || m.Mark(1, 2) != NoMark {
+		t.Error("(1, 2) is not auto marked")
+	}
+
+	m.Handle("a")
+	if b := m.Board(); b.Get(1, 2) != board1.Empty {
+		t.Errorf("got %s without auto marks", b.Get(1, 2))
+	}
+
+	// A cross stays when the queen is removed.
+	m.AutoMark = true
+	play(m, "x", board1.Position{2, 3}, board1.Position{1, 0})
+	play(m, KeyEnter, board1.Position{0, 1})
+	b = m.Board()
+	if b.Get(1, 0) != board1.Blocked || b.Get(2, 3) != board1.Blocked || b.Get(0, 0) != board1.Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	m, _ := newModel(t, 4)
+	var got []Mark
+	for range 3 {
+		m.Handle(" ")
+		got = append(got, m.Mark(0, 0))
+	}
+	if want := []Mark{Cross, Crown, NoMark}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	m, _ := newModel(t, 4)
+	play(m, KeyEnter, board1.Position{0, 1}, board1.Position{1, 3})
+	m.Handle("u")
+	if m.Queens() != 1 || m.Mark(1, 3) != NoMark {
+		t.Fatalf("got %d queens after undo", m.Queens())
+	}
+	m.Handle("r")
+	if m.Queens() != 2 {
+		t.Fatalf("got %d queens after redo", m.Queens())
+	}
+	m.Handle("u")
+	m.Handle("u")
+	if m.Undo() || m.Queens() != 0 {
+		t.Fatalf("got %d queens after undoing all", m.Queens())
+	}
+
+	// A change drops the changes to redo.
+	play(m, "x", board1.Position{2, 2})
+	if m.Redo() {
+		t.Error("redo after a change")
+	}
+	m.Handle("c")
+	m.Handle("u")
+	if m.Mark(2, 2) != Cross {
+		t.Error("undo of clear lost the cross")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	m, _ := newModel(t, 4)
+	play(m, KeyEnter, board1.Position{0, 0}, board1.Position{1, 1}, board1.Position{3, 3})
+	got := m.Conflicts()
+	want := []board1.Position{{0, 0}, {1, 1}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got conflicts %v, want %v", got, want)
+	}
+}
+
+func TestStars(t *testing.T) {
+	g := rowAreas(8)
+	g.Stars = 2
+	m, err := New(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One crown blocks only its neighbours, the second fills the row.
+	play(m, KeyEnter, board1.Position{0, 0})
+	if b := m.Board(); b.Get(1, 1) != board1.Blocked || b.Get(0, 2) != board1.Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+	play(m, KeyEnter, board1.Position{0, 4})
+	if b := m.Board(); b.Get(0, 2) != board1.Blocked || b.Get(2, 0) != board1.Empty || len(m.Conflicts()) > 0 {
+		t.Errorf("got board %v, conflicts %v", b.Fields, m.Conflicts())
+	}
+
+	// A third crown in the row and a crown next to another conflict.
+	play(m, KeyEnter, board1.Position{0, 2}, board1.Position{1, 5})
+	want := []board1.Position{{0, 0}, {0, 2}, {0, 4}, {1, 5}}
+	if got := m.Conflicts(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got conflicts %v, want %v", got, want)
+	}
+	m.Handle("c")
+
+	for i, s := range m.solution.Fields {
+		if s == board1.Queen {
+			play(m, KeyEnter, board1.Position{i / 8, i % 8})
+		}
+	}
+	if !m.Solved() || m.Queens() != 16 {
+		t.Errorf("not solved with %d queens", m.Queens())
+	}
+}
+
+func TestAdjacency(t *testing.T) {
+	g := rowAreas(6)
+	g.Adjacency = board1.KnightAdjacency
+	m, err := New(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	play(m, KeyEnter, board1.Position{0, 0})
+	if b := m.Board(); b.Get(1, 2) != board1.Blocked || b.Get(1, 1) != board1.Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+	play(m, KeyEnter, board1.Position{1, 1}, board1.Position{2, 3})
+	// Queens a knight's move apart conflict, neighbours do not.
+	want := []board1.Position{{1, 1}, {2, 3}}
+	if got := m.Conflicts(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got conflicts %v, want %v", got, want)
+	}
+}
+
+func TestDiagonals(t *testing.T) {
+	m, err := New(board1.NQueens(5), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	play(m, KeyEnter, board1.Position{0, 0})
+	if b := m.Board(); b.Get(4, 4) != board1.Blocked || b.Get(2, 1) != board1.Empty {
+		t.Errorf("got board %v", b.Fields)
+	}
+	play(m, KeyEnter, board1.Position{3, 3})
+	if got := m.Conflicts(); len(got) != 2 {
+		t.Errorf("got conflicts %v", got)
+	}
+	m.Handle("c")
+	for _, p := range []board1.Position{{0, 0}, {1, 2}, {2, 4}, {3, 1}, {4, 3}} {
+		play(m, KeyEnter, p)
+	}
+	if !m.Solved() {
+		t.Error("not solved")
+	}
+}
+
+func TestHoles(t *testing.T) {
+	g, err := board1.LoadGame(strings.NewReader("0.111\n00213\n.2233\n44433\n4.443\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	play(m, KeyEnter, board1.Position{0, 1})
+	if m.Queens() != 0 || m.Board().Get(0, 1) != board1.Hole {
+		t.Errorf("got board %v", m.Board().Fields)
+	}
+	for _, p := range []board1.Position{{0, 0}, {1, 3}, {2, 1}, {3, 4}, {4, 2}} {
+		play(m, KeyEnter, p)
+	}
+	if !m.Solved() {
+		t.Error("not solved")
+	}
+	if m.AutoMarked(2, 0) {
+		t.Error("hole is marked")
+	}
+}
+
+func TestSolved(t *testing.T) {
+	for _, cols := range [][]int{{1, 3, 0, 2}, {2, 0, 3, 1}} {
+		m, now := newModel(t, 4)
+		*now = now.Add(83 * time.Second)
+		for row, col := range cols[:3] {
+			play(m, KeyEnter, board1.Position{row, col})
+		}
+		if m.Solved() {
+			t.Fatal("solved with 3 queens")
+		}
+		play(m, KeyEnter, board1.Position{3, cols[3]})
+		if !m.Solved() {
+			t.Fatalf("%v: not solved", cols)
+		}
+		*now = now.Add(time.Minute)
+		if m.Elapsed() != 83*time.Second {
+			t.Errorf("got time %v after solving", m.Elapsed())
+		}
+
+		// A solved game does not change.
+		play(m, KeyEnter, board1.Position{0, cols[0]})
+		if m.Undo() || m.Queens() != 4 {
+			t.Error("solved game changed")
+		}
+
+		var buf bytes.Buffer
+		if err := m.Render(&buf, board1.PlainText); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "solved in 01:23!") {
+			t.Errorf("got screen %q", buf.String())
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	g := rowAreas(4)
+	start := &board1.Board{Fields: make([]board1.State, 16), Rows: 4, Cols: 4}
+	g.PlaceQueen(start, 0, 1)
+	start.Put(2, 3, board1.Blocked)
+	m, err := New(g, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the fields the queen does not block become crosses.
+	if m.Mark(0, 1) != Crown || m.Mark(2, 3) != Cross || m.Mark(1, 1) != NoMark {
+		t.Errorf("got marks %v", m.marks)
+	}
+
+	if _, err := New(rowAreas(3), nil); !errors.Is(err, board1.ErrNoSolution) {
+		t.Errorf("got error %v, want %v", err, board1.ErrNoSolution)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got, rest := ParseKeys([]byte("\x1b[A\x1bOBx \r\x1b[1;5Cq\x12\x03\x1bu"))
+	want := []Key{KeyUp, KeyDown, "x", " ", KeyEnter, "q", KeyCtrlR, KeyCtrlC, KeyEsc, "u"}
+	if !slices.Equal(got, want) || rest != nil {
+		t.Errorf("got %q, %q, want %q", got, rest, want)
+	}
+
+	// A sequence split over two reads.
+	got, rest = ParseKeys([]byte("h\x1b"))
+	if !slices.Equal(got, []Key{"h"}) || string(rest) != "\x1b" {
+		t.Errorf("got %q, %q", got, rest)
+	}
+	got, rest = ParseKeys(append(rest, '['))
+	if got != nil || string(rest) != "\x1b[" {
+		t.Errorf("got %q, %q", got, rest)
+	}
+	got, rest = ParseKeys(append(rest, 'D'))
+	if !slices.Equal(got, []Key{KeyLeft}) || rest != nil {
+		t.Errorf("got %q, %q", got, rest)
+	}
+	m, _ := newModel(t, 4)
+	if !m.Handle("x") || !m.Handle(KeyEsc) || m.Handle("q") || m.Handle(KeyCtrlC) {
+		t.Error("wrong keys quit")
+	}
+}
+
+func TestRender(t *testing.T) {
+	m, _ := newModel(t, 4)
+	play(m, KeyEnter, board1.Position{0, 0}, board1.Position{1, 1})
+	m.Row, m.Col = 3, 3
+
+	var buf bytes.Buffer
+	if err := m.Render(&buf, board1.PlainText); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	for _, want := range []string{" Q!", "[ ]", "queens 2/4", "conflicts 2", Help} {
+		if !strings.Contains(s, want) {
+			t.Errorf("screen has no %q:\n%s", want, s)
+		}
+	}
+
+	buf.Reset()
+	if err := m.Render(&buf, board1.TrueColor); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); !strings.Contains(s, "\x1b[31m ♛ ") || !strings.Contains(s, "\x1b[7m") || !strings.Contains(s, " · ") {
+		t.Errorf("got screen %q", s)
+	}
+}
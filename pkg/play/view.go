@@ -0,0 +1,92 @@
+package play
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// Render draws m on a full screen terminal in raw mode. In PlainText mode
+// the cursor is shown with brackets, crowns in conflict with a '!'.
+func (m *Model) Render(w io.Writer, mode board1.ColorMode) error {
+	r := board1.Renderer{Mode: mode}
+	area := make([]int, m.Game.Rows*m.Game.Cols)
+	for i := range area {
+		area[i] = -1
+	}
+	for i, a := range m.Game.Areas {
+		for _, p := range a.Cells {
+			area[p[0]*m.Game.Cols+p[1]] = i
+		}
+	}
+	conflicts := m.Conflicts()
+	b := m.Board()
+
+	bw := bufio.NewWriter(w)
+	// Home and clear the screen.
+	bw.WriteString("\x1b[H\x1b[2J")
+	for row := range m.Game.Rows {
+		for col := range m.Game.Cols {
+			cursor := row == m.Row && col == m.Col
+			conflict := slices.ContainsFunc(conflicts, func(p board1.Position) bool { return p[0] == row && p[1] == col })
+			s := b.Get(row, col)
+			if mode == board1.PlainText {
+				l, r := " ", " "
+				if cursor {
+					l, r = "[", "]"
+				}
+				if conflict {
+					r = "!"
+				}
+				fmt.Fprintf(bw, "%s%s%s", l, s, r)
+				continue
+			}
+			if a := area[row*m.Game.Cols+col]; a >= 0 {
+				bw.WriteString(r.Background(m.Game.AreaColor(a)))
+			}
+			if cursor {
+				bw.WriteString("\x1b[7m")
+			}
+			switch {
+			case conflict:
+				bw.WriteString("\x1b[31m ♛ ")
+			case s == board1.Queen:
+				bw.WriteString(" ♛ ")
+			case s == board1.Blocked && m.AutoMarked(row, col):
+				bw.WriteString(" · ")
+			case s == board1.Blocked:
+				bw.WriteString(" × ")
+			default:
+				bw.WriteString("   ")
+			}
+			bw.WriteString("\x1b[0m")
+		}
+		bw.WriteString("\r\n")
+	}
+	bw.WriteString("\r\n")
+
+	fmt.Fprintf(bw, "time %s  queens %d/%d", clock(m.Elapsed()), m.Queens(), m.Game.Queens())
+	if len(conflicts) > 0 {
+		fmt.Fprintf(bw, "  conflicts %d", len(conflicts))
+	}
+	if !m.AutoMark {
+		bw.WriteString("  auto marks off")
+	}
+	bw.WriteString("\r\n")
+	if m.Solved() {
+		fmt.Fprintf(bw, "solved in %s! press q to quit\r\n", clock(m.Elapsed()))
+	} else {
+		bw.WriteString(Help + "\r\n")
+	}
+	return bw.Flush()
+}
+
+// clock formats d as minutes and seconds.
+func clock(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
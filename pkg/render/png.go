@@ -0,0 +1,115 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// PNG writes g as a PNG image, with the queens and marks of b if b is
+// not nil.
+func PNG(w io.Writer, g *board1.Game, b *board1.Board, o Options) error {
+	img, err := Image(g, b, o)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// Image draws g, with the queens and marks of b if b is not nil.
+func Image(g *board1.Game, b *board1.Board, o Options) (*image.RGBA, error) {
+	l := newLayout(g, o)
+	colours, err := fills(g)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, l.width(), l.height()))
+	fill := func(r image.Rectangle, c color.Color) {
+		draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	fill(img.Bounds(), color.White)
+	for row := range l.rows {
+		for col := range l.cols {
+			if a := l.at(row, col); a >= 0 {
+				fill(image.Rect(l.x(col), l.y(row), l.x(col+1), l.y(row+1)), colours[a])
+			}
+		}
+	}
+	for _, s := range l.segments() {
+		if !s.thick {
+			fill(image.Rect(s.x0, s.y0, s.x1+1, s.y1+1), lineColor)
+		}
+	}
+	for _, s := range l.segments() {
+		if s.thick {
+			lo, hi := l.border/2, l.border-l.border/2
+			fill(image.Rect(s.x0-lo, s.y0-lo, s.x1+hi, s.y1+hi), borderColor)
+		}
+	}
+
+	for row := range l.rows {
+		for col := range l.cols {
+			r := image.Rect(l.x(col), l.y(row), l.x(col+1), l.y(row+1))
+			switch state(b, row, col) {
+			case board1.Queen:
+				paint(img, r, queenColor, func(u, v float64) bool {
+					return inPolygon(crown, u, v)
+				})
+			case board1.Blocked:
+				if !o.Marks {
+					continue
+				}
+				width := float64(max(l.field/16, 1)) / float64(l.field)
+				paint(img, r, markColor, func(u, v float64) bool {
+					for _, c := range cross {
+						if distance(c, u, v) <= width/2 {
+							return true
+						}
+					}
+					return false
+				})
+			}
+		}
+	}
+	return img, nil
+}
+
+// paint sets the pixels of field r for which in returns true. in gets the
+// middle of the pixel in parts of the field.
+func paint(img *image.RGBA, r image.Rectangle, c color.RGBA, in func(u, v float64) bool) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			u := (float64(x-r.Min.X) + 0.5) / float64(r.Dx())
+			v := (float64(y-r.Min.Y) + 0.5) / float64(r.Dy())
+			if in(u, v) {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// inPolygon reports whether u, v is inside polygon, by the even-odd rule.
+func inPolygon(polygon [][2]float64, u, v float64) bool {
+	in := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a[1] > v) != (b[1] > v) && u < (b[0]-a[0])*(v-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+// distance returns the distance from u, v to line segment s.
+func distance(s [2][2]float64, u, v float64) float64 {
+	dx, dy := s[1][0]-s[0][0], s[1][1]-s[0][1]
+	t := ((u-s[0][0])*dx + (v-s[0][1])*dy) / (dx*dx + dy*dy)
+	t = max(0, min(1, t))
+	return math.Hypot(u-(s[0][0]+t*dx), v-(s[0][1]+t*dy))
+}
@@ -0,0 +1,176 @@
+// Package render draws games and boards as SVG and PNG images.
+//
+// Areas are filled with their colour and separated by thick borders,
+// fields within an area by thin lines. Holes are left blank. Queens are drawn as crowns and
+// blocked fields, when asked for, as crosses.
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// Options change the drawing.
+type Options struct {
+	// Field is the width of a field in pixels, DefaultField if 0.
+	Field int
+	// Marks draws a cross on blocked fields.
+	Marks bool
+}
+
+// DefaultField is the width of a field if Options.Field is not set.
+const DefaultField = 48
+
+var (
+	lineColor   = color.RGBA{0x40, 0x40, 0x40, 0xff}
+	borderColor = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	queenColor  = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	markColor   = color.RGBA{0x40, 0x40, 0x40, 0xff}
+)
+
+// crown is the outline of a queen, in parts of a field.
+var crown = [][2]float64{
+	{0.2, 0.75}, {0.2, 0.35}, {0.35, 0.55}, {0.5, 0.25},
+	{0.65, 0.55}, {0.8, 0.35}, {0.8, 0.75},
+}
+
+// cross holds the two lines of a blocked mark, in parts of a field.
+var cross = [2][2][2]float64{
+	{{0.35, 0.35}, {0.65, 0.65}},
+	{{0.65, 0.35}, {0.35, 0.65}},
+}
+
+// layout holds the sizes used by both formats.
+type layout struct {
+	field  int
+	border int
+	margin int
+	rows   int
+	cols   int
+	// area holds the area of every field, -1 if it has none and hole for
+	// a hole.
+	area []int
+}
+
+// hole is the area of a hole and of the fields outside the board.
+const hole = -2
+
+func newLayout(g *board1.Game, o Options) *layout {
+	l := &layout{
+		field: o.Field,
+		rows:  g.Rows,
+		cols:  g.Cols,
+		area:  make([]int, g.Rows*g.Cols),
+	}
+	if l.field <= 0 {
+		l.field = DefaultField
+	}
+	l.border = max(l.field/12, 2)
+	l.margin = l.border
+	for i := range l.area {
+		l.area[i] = -1
+	}
+	for i, a := range g.Areas {
+		for _, p := range a.Cells {
+			if p[0] >= 0 && p[0] < g.Rows && p[1] >= 0 && p[1] < g.Cols {
+				l.area[p[0]*g.Cols+p[1]] = i
+			}
+		}
+	}
+	for _, h := range g.Holes {
+		if h[0] >= 0 && h[0] < g.Rows && h[1] >= 0 && h[1] < g.Cols {
+			l.area[h[0]*g.Cols+h[1]] = hole
+		}
+	}
+	return l
+}
+
+func (l *layout) width() int {
+	return 2*l.margin + l.cols*l.field
+}
+
+func (l *layout) height() int {
+	return 2*l.margin + l.rows*l.field
+}
+
+// at returns the area of row, col, hole outside the board.
+func (l *layout) at(row, col int) int {
+	if row < 0 || row >= l.rows || col < 0 || col >= l.cols {
+		return hole
+	}
+	return l.area[row*l.cols+col]
+}
+
+// x and y return the pixel of the top left corner of a field.
+func (l *layout) x(col int) int {
+	return l.margin + col*l.field
+}
+
+func (l *layout) y(row int) int {
+	return l.margin + row*l.field
+}
+
+// segment is a border between two fields.
+type segment struct {
+	x0, y0, x1, y1 int
+	thick          bool
+}
+
+// segments returns the borders between the fields and around the board.
+// A border is thick between areas and around holes, holes have no
+// borders between them.
+func (l *layout) segments() []segment {
+	var res []segment
+	add := func(s segment, a, b int) {
+		if a == hole && b == hole {
+			return
+		}
+		s.thick = a != b
+		res = append(res, s)
+	}
+	for row := range l.rows {
+		for col := range l.cols + 1 {
+			add(segment{x0: l.x(col), y0: l.y(row), x1: l.x(col), y1: l.y(row + 1)},
+				l.at(row, col-1), l.at(row, col))
+		}
+	}
+	for row := range l.rows + 1 {
+		for col := range l.cols {
+			add(segment{x0: l.x(col), y0: l.y(row), x1: l.x(col + 1), y1: l.y(row)},
+				l.at(row-1, col), l.at(row, col))
+		}
+	}
+	return res
+}
+
+// parseColor parses a colour like "#ffc992".
+func parseColor(s string) (color.RGBA, error) {
+	c := color.RGBA{A: 0xff}
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("invalid colour %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// fills returns the colour of every area of g.
+func fills(g *board1.Game) ([]color.RGBA, error) {
+	res := make([]color.RGBA, len(g.Areas))
+	for i := range g.Areas {
+		c, err := parseColor(g.AreaColor(i))
+		if err != nil {
+			return nil, err
+		}
+		res[i] = c
+	}
+	return res, nil
+}
+
+// state returns the state of a field of b, Empty if b is nil.
+func state(b *board1.Board, row, col int) board1.State {
+	if b == nil {
+		return board1.Empty
+	}
+	return b.Get(row, col)
+}
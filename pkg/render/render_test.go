@@ -0,0 +1,143 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// testGame returns a 4x4 game with an area per row, and its first solution.
+func testGame(t *testing.T) (*board1.Game, *board1.Board) {
+	t.Helper()
+	var areas []board1.Area
+	for row := range 4 {
+		var cells []board1.Position
+		for col := range 4 {
+			cells = append(cells, board1.Position{row, col})
+		}
+		areas = append(areas, board1.NewArea(board1.DefaultLabel(row), cells...))
+	}
+	areas[0].Color = "#ff7b60"
+	g := board1.NewGame(4, 4, areas...)
+	b, _, err := g.Solve(&board1.BitSolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, b.Clone()
+}
+
+func TestSVG(t *testing.T) {
+	g, b := testGame(t)
+	var buf bytes.Buffer
+	if err := SVG(&buf, g, b, Options{Field: 40, Marks: true}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	// The output must be well formed XML.
+	d := xml.NewDecoder(strings.NewReader(out))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid svg: %v\n%s", err, out)
+		}
+	}
+	if n := strings.Count(out, `class="queen"`); n != 4 {
+		t.Errorf("got %d queens, want 4", n)
+	}
+	if n := strings.Count(out, `class="mark"`); n != 2*12 {
+		t.Errorf("got %d mark lines, want %d", n, 2*12)
+	}
+	if !strings.Contains(out, `fill="#ff7b60"`) {
+		t.Error("area colour missing")
+	}
+
+	buf.Reset()
+	if err := SVG(&buf, g, nil, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "queen") {
+		t.Error("puzzle without board has queens")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	g, b := testGame(t)
+	var buf bytes.Buffer
+	if err := PNG(&buf, g, b, Options{Field: 40}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := newLayout(g, Options{Field: 40})
+	rgba := func(x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+	for row := range 4 {
+		for col := range 4 {
+			// The bottom right of a field is always the area colour.
+			x, y := l.x(col)+l.field*9/10, l.y(row)+l.field*9/10
+			want, _ := parseColor(g.AreaColor(row))
+			if got := rgba(x, y); got != want {
+				t.Errorf("field (%d, %d): got colour %v, want %v", row, col, got, want)
+			}
+			// The middle of the crown is black on a queen.
+			x, y = l.x(col)+l.field/2, l.y(row)+l.field*6/10
+			if got := rgba(x, y); (got == queenColor) != (b.Get(row, col) == board1.Queen) {
+				t.Errorf("field (%d, %d): got colour %v for a %s", row, col, got, b.Get(row, col))
+			}
+		}
+	}
+	// Rows are different areas, the border between them is thick.
+	if got := rgba(l.x(1)+l.field/2, l.y(1)+1); got != borderColor {
+		t.Errorf("got border colour %v, want %v", got, borderColor)
+	}
+	// Fields of one area have a thin line.
+	if got := rgba(l.x(1), l.y(1)+l.field/2); got != lineColor {
+		t.Errorf("got line colour %v, want %v", got, lineColor)
+	}
+}
+
+func TestHoles(t *testing.T) {
+	g := board1.NewGame(2, 2, board1.NewArea("a", board1.Position{0, 0}, board1.Position{1, 0}))
+	g.Holes = []board1.Position{{0, 1}, {1, 1}}
+	o := Options{Field: 40}
+	l := newLayout(g, o)
+	// Holes have no borders between them, and thick ones to the area.
+	var thick, thin int
+	for _, s := range l.segments() {
+		if s.thick {
+			thick++
+		} else {
+			thin++
+		}
+	}
+	if thick != 6 || thin != 1 {
+		t.Errorf("got %d thick and %d thin borders, want 6 and 1", thick, thin)
+	}
+
+	var buf bytes.Buffer
+	if err := PNG(&buf, g, nil, o); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if got := color.RGBAModel.Convert(img.At(l.x(1)+l.field/2, l.y(0)+l.field/2)); got != white {
+		t.Errorf("got hole colour %v, want white", got)
+	}
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// SVG writes g as an SVG image, with the queens and marks of b if b is
+// not nil.
+func SVG(w io.Writer, g *board1.Game, b *board1.Board, o Options) error {
+	l := newLayout(g, o)
+	colours, err := fills(g)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		l.width(), l.height(), l.width(), l.height())
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", l.width(), l.height())
+
+	for row := range l.rows {
+		for col := range l.cols {
+			if a := l.at(row, col); a >= 0 {
+				fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+					l.x(col), l.y(row), l.field, l.field, hex(colours[a]))
+			}
+		}
+	}
+	for _, s := range l.segments() {
+		if !s.thick {
+			fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`+"\n",
+				s.x0, s.y0, s.x1, s.y1, hex(lineColor))
+		}
+	}
+	for _, s := range l.segments() {
+		if s.thick {
+			fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" stroke-linecap="square"/>`+"\n",
+				s.x0, s.y0, s.x1, s.y1, hex(borderColor), l.border)
+		}
+	}
+
+	for row := range l.rows {
+		for col := range l.cols {
+			x, y, f := float64(l.x(col)), float64(l.y(row)), float64(l.field)
+			switch state(b, row, col) {
+			case board1.Queen:
+				points := make([]string, len(crown))
+				for i, p := range crown {
+					points[i] = fmt.Sprintf("%g,%g", x+p[0]*f, y+p[1]*f)
+				}
+				fmt.Fprintf(bw, `<polygon class="queen" points="%s" fill="%s"/>`+"\n",
+					strings.Join(points, " "), hex(queenColor))
+			case board1.Blocked:
+				if !o.Marks {
+					continue
+				}
+				for _, c := range cross {
+					fmt.Fprintf(bw, `<line class="mark" x1="%g" y1="%g" x2="%g" y2="%g" stroke="%s" stroke-width="%d"/>`+"\n",
+						x+c[0][0]*f, y+c[0][1]*f, x+c[1][0]*f, y+c[1][1]*f, hex(markColor), max(l.field/16, 1))
+				}
+			}
+		}
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
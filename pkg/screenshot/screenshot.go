@@ -0,0 +1,251 @@
+// Package screenshot reads Queens puzzles from screenshots of the grid.
+//
+// The grid must have dark lines on light coloured fields, like the LinkedIn
+// game in light mode. The screenshot may show more than the grid, as long
+// as nothing else has long dark lines.
+package screenshot
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"slices"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+var ErrNoGrid = errors.New("no grid found in the image")
+
+const (
+	// darkLum is the luminance below which a pixel is part of a line,
+	// or of a queen or cross on a field.
+	darkLum = 128
+	// lineFill is the part of the longest line in percent that a row or
+	// column of pixels must be dark to be a line of the grid.
+	lineFill = 50
+)
+
+// Load reads a PNG or JPEG screenshot and returns the areas of the puzzle
+// and the size of the board, like board1.LoadAreas.
+func Load(r io.Reader) ([]board1.Area, int, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode screenshot: %w", err)
+	}
+	return Decode(img)
+}
+
+// Decode finds the grid in img, samples the colour of every field and
+// groups the fields with the closest colours into as many areas as the
+// grid has rows. The areas are labelled in the order they first appear,
+// and get the colour of their fields.
+// The puzzle is validated, issues are returned as a *board1.ValidationError.
+func Decode(img image.Image) ([]board1.Area, int, error) {
+	b := img.Bounds()
+	cols := make([]int, b.Dx())
+	rows := make([]int, b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if lum(rgbAt(img, x, y)) < darkLum {
+				cols[x-b.Min.X]++
+				rows[y-b.Min.Y]++
+			}
+		}
+	}
+	xs, err := gridLines(cols)
+	if err != nil {
+		return nil, 0, err
+	}
+	ys, err := gridLines(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(xs) - 1
+	if len(ys)-1 != n {
+		return nil, 0, fmt.Errorf("grid has %d rows and %d columns", len(ys)-1, n)
+	}
+	// Clustering takes time with the cube of the fields, and the solvers
+	// do not hold larger boards anyway.
+	if n*n > board1.MaxBitFields {
+		return nil, 0, fmt.Errorf("grid of %dx%d has more than %d fields", n, n, board1.MaxBitFields)
+	}
+
+	colours := make([]rgb, n*n)
+	for row := range n {
+		for col := range n {
+			r := image.Rect(xs[col], ys[row], xs[col+1], ys[row+1]).Add(b.Min)
+			colours[row*n+col] = sample(img, r)
+		}
+	}
+
+	areas := toAreas(n, colours, cluster(colours, n))
+	if err := board1.Validate(board1.NewGame(n, n, areas...)); err != nil {
+		return nil, 0, err
+	}
+	return areas, n, nil
+}
+
+// gridLines returns the borders of the fields from a profile that holds
+// the dark pixels of every column, or row, of pixels. The borders are
+// evenly spaced between the outer lines, the width of a field is the
+// median distance between the lines found.
+func gridLines(profile []int) ([]int, error) {
+	longest := slices.Max(profile)
+	if longest == 0 {
+		return nil, ErrNoGrid
+	}
+	// centers holds the middle of every run of line pixels.
+	var centers []int
+	start := -1
+	for i := 0; i <= len(profile); i++ {
+		line := i < len(profile) && profile[i]*100 >= longest*lineFill
+		switch {
+		case line && start < 0:
+			start = i
+		case !line && start >= 0:
+			centers = append(centers, (start+i-1)/2)
+			start = -1
+		}
+	}
+	if len(centers) < 2 {
+		return nil, ErrNoGrid
+	}
+
+	gaps := make([]int, len(centers)-1)
+	for i := range gaps {
+		gaps[i] = centers[i+1] - centers[i]
+	}
+	slices.Sort(gaps)
+	field := gaps[len(gaps)/2]
+
+	first, last := centers[0], centers[len(centers)-1]
+	n := (last - first + field/2) / field
+	if n < 1 {
+		return nil, ErrNoGrid
+	}
+	res := make([]int, n+1)
+	for i := range res {
+		res[i] = first + i*(last-first)/n
+	}
+	return res, nil
+}
+
+type rgb struct {
+	r, g, b int
+}
+
+func rgbAt(img image.Image, x, y int) rgb {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return rgb{int(r >> 8), int(g >> 8), int(b >> 8)}
+}
+
+func lum(c rgb) int {
+	return (299*c.r + 587*c.g + 114*c.b) / 1000
+}
+
+func (c rgb) dist(o rgb) int {
+	dr, dg, db := c.r-o.r, c.g-o.g, c.b-o.b
+	return dr*dr + dg*dg + db*db
+}
+
+func (c rgb) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+}
+
+// sample returns the mean colour of the middle of field r. Dark pixels,
+// like queens and crosses, are skipped unless the field has nothing else.
+func sample(img image.Image, r image.Rectangle) rgb {
+	inner := r.Inset(min(r.Dx(), r.Dy()) / 4)
+	var all, light rgb
+	var nAll, nLight int
+	for y := inner.Min.Y; y < inner.Max.Y; y++ {
+		for x := inner.Min.X; x < inner.Max.X; x++ {
+			c := rgbAt(img, x, y)
+			all = rgb{all.r + c.r, all.g + c.g, all.b + c.b}
+			nAll++
+			if lum(c) >= darkLum {
+				light = rgb{light.r + c.r, light.g + c.g, light.b + c.b}
+				nLight++
+			}
+		}
+	}
+	sum, n := light, nLight
+	if n == 0 {
+		sum, n = all, nAll
+	}
+	if n == 0 {
+		return rgb{}
+	}
+	return rgb{sum.r / n, sum.g / n, sum.b / n}
+}
+
+// cluster groups colours in n clusters. It starts with a cluster per
+// colour and merges the two clusters with the closest mean colours until
+// n are left. It returns the cluster of every colour.
+func cluster(colours []rgb, n int) []int {
+	type group struct {
+		sum     rgb
+		mean    rgb
+		members []int
+	}
+	groups := make([]group, len(colours))
+	for i, c := range colours {
+		groups[i] = group{sum: c, mean: c, members: []int{i}}
+	}
+	for len(groups) > n {
+		bi, bj, best := 0, 1, -1
+		for i := range groups {
+			for j := i + 1; j < len(groups); j++ {
+				if d := groups[i].mean.dist(groups[j].mean); best < 0 || d < best {
+					bi, bj, best = i, j, d
+				}
+			}
+		}
+		gi, gj := groups[bi], groups[bj]
+		sum := rgb{gi.sum.r + gj.sum.r, gi.sum.g + gj.sum.g, gi.sum.b + gj.sum.b}
+		k := len(gi.members) + len(gj.members)
+		groups[bi] = group{
+			sum:     sum,
+			mean:    rgb{sum.r / k, sum.g / k, sum.b / k},
+			members: append(gi.members, gj.members...),
+		}
+		groups = slices.Delete(groups, bj, bj+1)
+	}
+
+	res := make([]int, len(colours))
+	for k, g := range groups {
+		for _, i := range g.members {
+			res[i] = k
+		}
+	}
+	return res
+}
+
+// toAreas returns the areas of a board of n x n fields, with clusters
+// holding the cluster of every field.
+func toAreas(n int, colours []rgb, clusters []int) []board1.Area {
+	var areas []board1.Area
+	// index holds the area of every cluster.
+	index := map[int]int{}
+	sums := map[int]rgb{}
+	for f, c := range clusters {
+		i, ok := index[c]
+		if !ok {
+			i = len(areas)
+			index[c] = i
+			areas = append(areas, board1.Area{Label: board1.DefaultLabel(i)})
+		}
+		areas[i].Cells = append(areas[i].Cells, board1.Position{f / n, f % n})
+		s := sums[i]
+		sums[i] = rgb{s.r + colours[f].r, s.g + colours[f].g, s.b + colours[f].b}
+	}
+	for i := range areas {
+		k := len(areas[i].Cells)
+		s := sums[i]
+		areas[i].Color = rgb{s.r / k, s.g / k, s.b / k}.String()
+	}
+	return areas
+}
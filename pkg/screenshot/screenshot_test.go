@@ -0,0 +1,153 @@
+package screenshot
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+var testLayout = []string{
+	"00111",
+	"02211",
+	"02331",
+	"44331",
+	"44433",
+}
+
+var testColours = []color.RGBA{
+	{0xbb, 0xa3, 0xe2, 0xff},
+	{0xff, 0xc9, 0x92, 0xff},
+	{0x96, 0xbe, 0xff, 0xff},
+	{0xb3, 0xdf, 0xa0, 0xff},
+	{0xdf, 0xdf, 0xdf, 0xff},
+}
+
+// drawGrid draws layout like the game does: thin lines between the
+// fields of an area, thick lines between areas, and a margin with
+// a title and a queen on the first field.
+func drawGrid(layout []string, field int) *image.RGBA {
+	const margin = 30
+	n := len(layout)
+	img := image.NewRGBA(image.Rect(0, 0, 2*margin+n*field, 3*margin+n*field))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	fill(margin, 5, margin+field, 15, color.Black)
+
+	top := 2 * margin
+	at := func(row, col int) byte {
+		if row < 0 || row >= n || col < 0 || col >= n {
+			return ' '
+		}
+		return layout[row][col]
+	}
+	for row := range n {
+		for col := range n {
+			x, y := margin+col*field, top+row*field
+			fill(x, y, x+field, y+field, testColours[at(row, col)-'0'])
+		}
+	}
+	thin := color.Gray{0x50}
+	for row := range n {
+		for col := range n + 1 {
+			x, y := margin+col*field, top+row*field
+			if at(row, col-1) != at(row, col) {
+				fill(x-2, y, x+2, y+field, color.Black)
+			} else {
+				fill(x, y, x+1, y+field, thin)
+			}
+		}
+	}
+	for row := range n + 1 {
+		for col := range n {
+			x, y := margin+col*field, top+row*field
+			if at(row-1, col) != at(row, col) {
+				fill(x, y-2, x+field, y+2, color.Black)
+			} else {
+				fill(x, y, x+field, y+1, thin)
+			}
+		}
+	}
+	// A queen on the first field.
+	fill(margin+field/2-5, top+field/2-5, margin+field/2+5, top+field/2+5, color.Black)
+	return img
+}
+
+func layoutOf(areas []board1.Area, n int) []string {
+	grid := make([][]byte, n)
+	for i := range grid {
+		grid[i] = bytes.Repeat([]byte{'?'}, n)
+	}
+	for _, a := range areas {
+		for _, p := range a.Cells {
+			grid[p[0]][p[1]] = a.Label[0]
+		}
+	}
+	res := make([]string, n)
+	for i, r := range grid {
+		res[i] = string(r)
+	}
+	return res
+}
+
+func TestLoad(t *testing.T) {
+	img := drawGrid(testLayout, 40)
+	tests := []struct {
+		name   string
+		encode func(*bytes.Buffer) error
+	}{
+		{"png", func(b *bytes.Buffer) error { return png.Encode(b, img) }},
+		{"jpeg", func(b *bytes.Buffer) error { return jpeg.Encode(b, img, &jpeg.Options{Quality: 75}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.encode(&buf); err != nil {
+				t.Fatal(err)
+			}
+			areas, n, err := Load(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(testLayout) {
+				t.Fatalf("got size %d, want %d", n, len(testLayout))
+			}
+			got := layoutOf(areas, n)
+			if strings.Join(got, "\n") != strings.Join(testLayout, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(testLayout, "\n"))
+			}
+			if tt.name == "png" && areas[0].Color != "#bba3e2" {
+				t.Errorf("got colour %s, want #bba3e2", areas[0].Color)
+			}
+		})
+	}
+}
+
+func TestLoadNoGrid(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 50, 50))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	if _, _, err := Decode(img); !errors.Is(err, ErrNoGrid) {
+		t.Errorf("got error %v, want %v", err, ErrNoGrid)
+	}
+}
+
+func TestLoadLargeGrid(t *testing.T) {
+	// A checkerboard of 40x40 fields.
+	layout := make([]string, 40)
+	for i := range layout {
+		layout[i] = strings.Repeat("01", 21)[i%2 : i%2+40]
+	}
+	_, _, err := Decode(drawGrid(layout, 12))
+	if err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Errorf("got error %v, want an error for the size", err)
+	}
+}
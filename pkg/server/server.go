@@ -0,0 +1,285 @@
+// Package server serves the solver over HTTP.
+//
+// Every endpoint takes a puzzle in the body, in JSON or in the text format,
+// and answers with JSON:
+//
+//	POST /solve            the solution
+//	POST /validate         the issues of the puzzle
+//	POST /count-solutions  the number of solutions, up to ?limit=
+//	POST /rate             the difficulty
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myhops/queens/pkg/board1"
+)
+
+// Server handles the requests. Its fields must not change after Handler
+// is called.
+type Server struct {
+	// MaxBytes is the largest request body.
+	MaxBytes int64
+	// MaxSize is the largest number of rows or columns of a board.
+	MaxSize int
+	// Timeout is the longest time a request may take.
+	Timeout time.Duration
+	// MaxCount is the highest limit for counting solutions.
+	MaxCount int
+	// Budget limits every search of a request.
+	Budget board1.Budget
+}
+
+// New returns a server with the default limits.
+func New() *Server {
+	return &Server{
+		MaxBytes: 64 << 10,
+		MaxSize:  16,
+		Timeout:  10 * time.Second,
+		MaxCount: 10000,
+	}
+}
+
+// Handler returns the handler of the endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /solve", s.handle(s.solve))
+	mux.HandleFunc("POST /validate", s.handleValidate)
+	mux.HandleFunc("POST /count-solutions", s.handle(s.count))
+	mux.HandleFunc("POST /rate", s.handle(s.rate))
+	return http.TimeoutHandler(mux, s.Timeout, `{"error":"request timed out"}`)
+}
+
+// httpError is an error with the status to answer with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+func (e *httpError) Unwrap() error {
+	return e.err
+}
+
+// errorBody is the response of a failed request.
+type errorBody struct {
+	Error  string         `json:"error"`
+	Issues []board1.Issue `json:"issues,omitempty"`
+}
+
+// handle wraps an endpoint that works on a valid puzzle.
+func (s *Server) handle(f func(r *http.Request, p *board1.Puzzle) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := s.load(w, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		res, err := f(r, p)
+		var se *board1.StoppedError
+		if errors.As(err, &se) {
+			err = &httpError{http.StatusServiceUnavailable, err}
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// load reads the puzzle in the body of r and checks its size.
+func (s *Server) load(w http.ResponseWriter, r *http.Request) (*board1.Puzzle, error) {
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(http.MaxBytesReader(w, r.Body, s.MaxBytes)); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return nil, &httpError{http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", mbe.Limit)}
+		}
+		return nil, &httpError{http.StatusBadRequest, err}
+	}
+	// Validation allocates and searches the board, check its size first.
+	if bytes.HasPrefix(bytes.TrimSpace(buf.Bytes()), []byte("{")) {
+		var head struct {
+			Size board1.Size `json:"size"`
+		}
+		if json.Unmarshal(buf.Bytes(), &head) == nil {
+			if err := s.checkSize(head.Size); err != nil {
+				return nil, err
+			}
+		}
+	} else if err := s.checkSize(textSize(buf.Bytes())); err != nil {
+		return nil, err
+	}
+	p, err := board1.LoadPuzzle(&buf)
+	var ve *board1.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		return nil, &httpError{http.StatusUnprocessableEntity, err}
+	case err != nil:
+		return nil, &httpError{http.StatusBadRequest, err}
+	}
+	if err := s.checkSize(p.Size); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// checkSize returns an httpError if size is larger than MaxSize.
+func (s *Server) checkSize(size board1.Size) error {
+	if size.Rows > s.MaxSize || size.Cols > s.MaxSize {
+		return &httpError{http.StatusRequestEntityTooLarge,
+			fmt.Errorf("board of %dx%d is larger than %dx%d", size.Rows, size.Cols, s.MaxSize, s.MaxSize)}
+	}
+	return nil
+}
+
+// textSize returns the number of rows of a puzzle in the text format and
+// the number of fields of its longest row.
+func textSize(b []byte) board1.Size {
+	var size board1.Size
+	for _, line := range strings.Split(string(b), "\n") {
+		n := 0
+		for _, c := range line {
+			if !strings.ContainsRune(" \t\r", c) {
+				n++
+			}
+		}
+		if n > 0 {
+			size.Rows++
+			size.Cols = max(size.Cols, n)
+		}
+	}
+	return size
+}
+
+// SolveResponse is the response of /solve.
+type SolveResponse struct {
+	Solution []board1.Position `json:"solution"`
+	// Board holds a row per string, with Q for a queen, X for
+	// a blocked field and . for an empty field.
+	Board []string          `json:"board"`
+	Stats board1.SolveStats `json:"stats"`
+}
+
+func (s *Server) solve(r *http.Request, p *board1.Puzzle) (any, error) {
+	g := p.Game()
+	b, stats, err := g.SolveContext(r.Context(), &board1.BitSolver{}, s.Budget)
+	if errors.Is(err, board1.ErrNoSolution) {
+		return nil, &httpError{http.StatusUnprocessableEntity, err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.SetSolution(b)
+
+	var buf bytes.Buffer
+	if err := board1.WritePosition(&buf, b); err != nil {
+		return nil, err
+	}
+	rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, row := range rows {
+		rows[i] = strings.ReplaceAll(row, "\t", "")
+	}
+	return SolveResponse{Solution: p.Solution, Board: rows, Stats: stats}, nil
+}
+
+// ValidateResponse is the response of /validate.
+type ValidateResponse struct {
+	Valid  bool           `json:"valid"`
+	Issues []board1.Issue `json:"issues,omitempty"`
+}
+
+// handleValidate answers 200 for invalid puzzles too, the issues are
+// the answer.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	_, err := s.load(w, r)
+	var ve *board1.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		writeJSON(w, http.StatusOK, ValidateResponse{Issues: ve.Issues})
+	case err != nil:
+		writeError(w, err)
+	default:
+		writeJSON(w, http.StatusOK, ValidateResponse{Valid: true})
+	}
+}
+
+// CountResponse is the response of /count-solutions.
+type CountResponse struct {
+	Count int `json:"count"`
+	// Limited is set when counting stopped at the limit.
+	Limited bool              `json:"limited"`
+	Stats   board1.SolveStats `json:"stats"`
+}
+
+func (s *Server) count(r *http.Request, p *board1.Puzzle) (any, error) {
+	limit := s.MaxCount
+	if q := r.URL.Query().Get("limit"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n <= 0 {
+			return nil, &httpError{http.StatusBadRequest, fmt.Errorf("invalid limit %q", q)}
+		}
+		limit = min(n, s.MaxCount)
+	}
+	n, stats, err := p.Game().CountSolutionsContext(r.Context(), &board1.BitSolver{}, limit, s.Budget)
+	if err != nil {
+		return nil, err
+	}
+	return CountResponse{Count: n, Limited: n >= limit, Stats: stats}, nil
+}
+
+// RateResponse is the response of /rate.
+type RateResponse struct {
+	Tier        string `json:"tier"`
+	Score       int    `json:"score"`
+	HardestRule string `json:"hardestRule,omitempty"`
+	Steps       int    `json:"steps"`
+	Branches    int    `json:"branches"`
+	NoSolution  bool   `json:"noSolution,omitempty"`
+}
+
+func (s *Server) rate(req *http.Request, p *board1.Puzzle) (any, error) {
+	r, err := board1.RateContext(req.Context(), p.Game(), s.Budget)
+	if err != nil {
+		return nil, err
+	}
+	return RateResponse{
+		Tier:        r.Tier.String(),
+		Score:       r.Score,
+		HardestRule: r.HardestRule,
+		Steps:       r.Steps,
+		Branches:    r.Branches,
+		NoSolution:  r.NoSolution,
+	}, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var he *httpError
+	if errors.As(err, &he) {
+		status = he.status
+	}
+	body := errorBody{Error: err.Error()}
+	var ve *board1.ValidationError
+	if errors.As(err, &ve) {
+		body.Issues = ve.Issues
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func post(t *testing.T, h http.Handler, path, body string, res any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	if res != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), res); err != nil {
+			t.Fatalf("%s: %v: %s", path, err, rec.Body.String())
+		}
+	}
+	return rec.Code
+}
+
+func puzzle(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSolve(t *testing.T) {
+	h := New().Handler()
+	var res SolveResponse
+	if code := post(t, h, "/solve", puzzle(t, "../../2025-04-24.txt"), &res); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if len(res.Solution) != 8 || res.Stats.Nodes == 0 || len(res.Board) != 8 || strings.Count(strings.Join(res.Board, ""), "Q") != 8 {
+		t.Errorf("got %+v", res)
+	}
+
+	// A puzzle in JSON, with an area per row.
+	js := `{"size":{"rows":4,"cols":4},"areas":[` +
+		`{"label":"a","cells":[[0,0],[0,1],[0,2],[0,3]]},{"label":"b","cells":[[1,0],[1,1],[1,2],[1,3]]},` +
+		`{"label":"c","cells":[[2,0],[2,1],[2,2],[2,3]]},{"label":"d","cells":[[3,0],[3,1],[3,2],[3,3]]}]}`
+	res = SolveResponse{}
+	if code := post(t, h, "/solve", js, &res); code != http.StatusOK || len(res.Solution) != 4 {
+		t.Errorf("got status %d, %+v", code, res)
+	}
+
+	var count CountResponse
+	if code := post(t, h, "/count-solutions", js, &count); code != http.StatusOK || count.Count != 2 || count.Limited || count.Stats.Placements == 0 {
+		t.Errorf("got status %d, %+v", code, count)
+	}
+	count = CountResponse{}
+	if code := post(t, h, "/count-solutions?limit=1", js, &count); code != http.StatusOK || count.Count != 1 || !count.Limited {
+		t.Errorf("got status %d, %+v", code, count)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	h := New().Handler()
+	var res ValidateResponse
+	if code := post(t, h, "/validate", "0 0\n1\n", &res); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if res.Valid || len(res.Issues) != 1 || res.Issues[0].Line != 2 {
+		t.Errorf("got %+v", res)
+	}
+
+	res = ValidateResponse{}
+	if code := post(t, h, "/validate", puzzle(t, "../../2025-4-22.txt"), &res); code != http.StatusOK || !res.Valid {
+		t.Errorf("got status %d, %+v", code, res)
+	}
+
+	// Other endpoints refuse invalid puzzles.
+	var e errorBody
+	if code := post(t, h, "/solve", "0 0\n1\n", &e); code != http.StatusUnprocessableEntity || len(e.Issues) != 1 {
+		t.Errorf("got status %d, %+v", code, e)
+	}
+}
+
+func TestRate(t *testing.T) {
+	h := New().Handler()
+	var res RateResponse
+	if code := post(t, h, "/rate", puzzle(t, "../../2025-04-23.txt"), &res); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if res.Tier != "easy" {
+		t.Errorf("got %+v", res)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s := New()
+	s.MaxBytes = 100
+	s.MaxSize = 4
+	h := s.Handler()
+
+	var e errorBody
+	if code := post(t, h, "/solve", strings.Repeat("0", 101), &e); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, %+v", code, e)
+	}
+	if code := post(t, h, "/solve", puzzle(t, "../../2025-04-24.txt")[:100], &e); code == http.StatusOK {
+		t.Errorf("got status %d for a cut puzzle", code)
+	}
+	s.MaxBytes = 1000
+	h = s.Handler()
+	if code := post(t, h, "/solve", puzzle(t, "../../2025-04-24.txt"), &e); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, %+v", code, e)
+	}
+
+	if code := post(t, h, "/solve", "{", &e); code != http.StatusBadRequest {
+		t.Errorf("got status %d for bad JSON", code)
+	}
+
+	// The size is checked before the puzzle is validated.
+	for body, want := range map[string]int{
+		`{"size":{"rows":-1,"cols":2},"areas":[]}`:                  http.StatusUnprocessableEntity,
+		`{"size":{"rows":3000000000,"cols":3000000000},"areas":[]}`: http.StatusRequestEntityTooLarge,
+		`{"size":{"rows":30000,"cols":30000},"areas":[]}`:           http.StatusRequestEntityTooLarge,
+	} {
+		if code := post(t, h, "/solve", body, &e); code != want {
+			t.Errorf("%s: got status %d, want %d", body, code, want)
+		}
+	}
+
+	// A large text board of one area is rejected before it is parsed, also
+	// with the default limits.
+	large := strings.Repeat(strings.Repeat("a", 240)+"\n", 240)
+	for _, path := range []string{"/solve", "/validate"} {
+		if code := post(t, New().Handler(), path, large, &e); code != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s: got status %d for a text board of 240x240, want %d", path, code, http.StatusRequestEntityTooLarge)
+		}
+	}
+	if code := post(t, h, "/solve", "0 0 0 0 0\n", &e); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d for a text row of 5 fields", code)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/solve", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("got status %d for GET", rec.Code)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := New()
+	s.Timeout = time.Nanosecond
+	var e errorBody
+	if code := post(t, s.Handler(), "/rate", puzzle(t, "../../2025-04-24.txt"), &e); code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, %+v", code, e)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	s := New()
+	s.Budget.Nodes = 1
+	h := s.Handler()
+	for _, path := range []string{"/solve", "/count-solutions", "/rate"} {
+		var e errorBody
+		if code := post(t, h, path, puzzle(t, "../../2025-04-24.txt"), &e); code != http.StatusServiceUnavailable {
+			t.Errorf("%s: got status %d, %+v", path, code, e)
+		}
+	}
+}